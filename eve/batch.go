@@ -0,0 +1,186 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package eve
+
+import (
+	"runtime"
+	"slices"
+
+	"cogentcore.org/core/tree"
+)
+
+// Batch is a batch of independent worlds that are copies of a Template
+// world, e.g., for training a model on many copies of an environment
+// at once.  The Dynamic top-level groups of the Template are cloned for
+// each world, while its static top-level groups are cloned only once,
+// into the Static group, which is Shared by all of the worlds, so that
+// the static geometry is not duplicated.  Each world has its own Solver,
+// and Step steps all of the worlds concurrently, applying the actions
+// for each world.  Views of a world do not show the Shared static
+// geometry, which can be viewed separately as the Static group.
+type Batch struct {
+
+	// template world that the worlds are copies of
+	Template *Group
+
+	// static geometry that is shared by all of the worlds, cloned from the static top-level groups of the Template
+	Static *Group
+
+	// the worlds, each of which has clones of the Dynamic top-level groups of the Template, with the same names
+	Worlds []*Group
+
+	// the solver for each world, which are copies of the Solver passed to NewBatch, and can be configured separately, e.g., with the Vehicles and Snaps in each world
+	Solvers []*Solver
+
+	// the contacts from the last Step of each world
+	Contacts [][]Contacts `display:"-"`
+
+	// number of goroutines used to step the worlds concurrently -- 0 uses runtime.NumCPU()
+	Workers int
+}
+
+// NewBatch returns a new Batch of n worlds that are copies of the given
+// template world, each with a copy of the given Solver, without any
+// of its Vehicles or Snaps, which refer to the nodes of the template.
+// The template is initialized with WorldInit, so that its top-level
+// groups are Dynamic if they contain Dynamic bodies, and the worlds are
+// initialized to their Initial states.
+func NewBatch(template *Group, n int, sv *Solver) *Batch {
+	bt := &Batch{Template: template}
+	template.WorldInit()
+	bt.Static = &Group{}
+	bt.Static.InitName(bt.Static, template.Name()+"-static")
+	bt.Static.Initial = template.Initial
+	for _, kid := range template.Kids {
+		if nii, _ := AsNode(kid); nii != nil && !nii.IsDynamic() {
+			bt.Static.AddChild(cloneNode(kid))
+		}
+	}
+	bt.Static.WorldInit()
+	bt.Worlds = make([]*Group, n)
+	bt.Solvers = make([]*Solver, n)
+	bt.Contacts = make([][]Contacts, n)
+	for wi := range n {
+		w := &Group{}
+		w.InitName(w, template.Name())
+		w.Initial = template.Initial
+		w.Shared = bt.Static
+		for _, kid := range template.Kids {
+			if nii, _ := AsNode(kid); nii != nil && nii.IsDynamic() {
+				w.AddChild(cloneNode(kid))
+			}
+		}
+		w.WorldInit()
+		bt.Worlds[wi] = w
+		bt.Solvers[wi] = copySolver(sv)
+	}
+	return bt
+}
+
+// cloneNode returns a clone of the given subtree, including the NodeFlags
+// of its nodes (e.g., Dynamic), which are not copied by Clone
+func cloneNode(k tree.Node) tree.Node {
+	c := k.Clone()
+	var src []*NodeBase
+	k.WalkDown(func(n tree.Node) bool {
+		_, ni := AsNode(n)
+		src = append(src, ni)
+		return true
+	})
+	i := 0
+	c.WalkDown(func(n tree.Node) bool {
+		_, ni := AsNode(n)
+		if ni != nil && src[i] != nil {
+			for _, f := range NodeFlagsValues() {
+				ni.SetFlag(src[i].Is(f), f)
+			}
+		}
+		i++
+		return true
+	})
+	return c
+}
+
+// copySolver returns a copy of the parameters of given Solver, without
+// any state or references to nodes
+func copySolver(sv *Solver) *Solver {
+	ns := &Solver{}
+	*ns = *sv
+	ns.Broad = SpatialHash{CellSize: sv.Broad.CellSize, MaxCells: sv.Broad.MaxCells}
+	ns.Fluids = slices.Clone(sv.Fluids)
+	ns.Bounds.Out = nil
+	ns.Vehicles = nil
+	ns.Snaps = nil
+	ns.Manifolds = nil
+	ns.Store = BodyStore{}
+	ns.Workers = 0
+	return ns
+}
+
+// Len returns the number of worlds
+func (bt *Batch) Len() int {
+	return len(bt.Worlds)
+}
+
+// workers returns the number of goroutines to use
+func (bt *Batch) workers() int {
+	if bt.Workers > 0 {
+		return bt.Workers
+	}
+	return runtime.NumCPU()
+}
+
+// Step does one Solver.Step of each of the worlds concurrently, first
+// calling act, if non-nil, with the index of each world, to apply the
+// actions for that world (e.g., moving its agent), and returns the
+// contacts of each world, which are also in Contacts.
+func (bt *Batch) Step(step float32, act func(wi int, world *Group)) [][]Contacts {
+	parallelFor(len(bt.Worlds), bt.workers(), func(wi int) {
+		w := bt.Worlds[wi]
+		if act != nil {
+			act(wi, w)
+		}
+		bt.Contacts[wi] = bt.Solvers[wi].Step(w, step)
+	})
+	return bt.Contacts
+}
+
+// Observe calls fun with each of the worlds concurrently, e.g., to
+// compute the observations of the agent in each world into a slice
+// indexed by the world index wi.
+func (bt *Batch) Observe(fun func(wi int, world *Group)) {
+	parallelFor(len(bt.Worlds), bt.workers(), func(wi int) {
+		fun(wi, bt.Worlds[wi])
+	})
+}
+
+// States returns the current Abs state of the node at given path in each
+// of the worlds (e.g., "agent/body"), stacked in order of the worlds,
+// with a zero state for any world that does not have it.
+func (bt *Batch) States(path string) []Phys {
+	ps := make([]Phys, len(bt.Worlds))
+	for wi, w := range bt.Worlds {
+		if _, ni := AsNode(w.FindPath(path)); ni != nil {
+			ps[wi] = ni.Abs
+		}
+	}
+	return ps
+}
+
+// Init reinitializes all of the worlds to their Initial states
+func (bt *Batch) Init() {
+	for wi := range bt.Worlds {
+		bt.InitWorld(wi)
+	}
+}
+
+// InitWorld reinitializes world wi to its Initial state, e.g., at the
+// end of an episode, clearing the contact Manifolds and Store of its Solver.
+func (bt *Batch) InitWorld(wi int) {
+	bt.Worlds[wi].WorldInit()
+	bt.Solvers[wi].Manifolds = nil
+	bt.Solvers[wi].Store.Invalidate()
+	bt.Contacts[wi] = nil
+}
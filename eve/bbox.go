@@ -15,7 +15,13 @@ type BBox struct {
 	// velocity-projected bounding box in world coords: extend BBox to include future position of moving bodies -- collision must be made on this basis
 	VelBBox math32.Box3
 
-	// bounding sphere in local coords
+	// oriented bounding box in world coords, which is tight around rotated bodies -- only for bodies, not groups
+	OBB OBB
+
+	// velocity-projected oriented bounding box in world coords, used for the midphase of collision detection after the VelBBox test -- only for bodies
+	VelOBB OBB
+
+	// bounding sphere in world coords
 	BSphere math32.Sphere
 
 	// area
@@ -39,25 +45,47 @@ func (bb *BBox) UpdateFromBBox() {
 	bb.Volume = sz.X * sz.Y * sz.Z
 }
 
-// XForm transforms bounds with given quat and position offset to convert to world coords
+// XForm transforms bounds in local coords with given quat and position offset
+// to convert to world coords, setting the OBB, the BBox that contains it,
+// and the BSphere with its center in world coords
 func (bb *BBox) XForm(q math32.Quat, pos math32.Vector3) {
-	bb.BBox = bb.BBox.MulQuat(q).Translate(pos)
-	bb.BSphere.Translate(pos)
+	bb.OBB.Set(bb.BBox, q, pos)
+	bb.BBox = bb.OBB.AABB()
+	bb.BSphere.Set(bb.OBB.Center, bb.BSphere.Radius)
+}
+
+// Translate moves all the bounds by given offset in world coords
+func (bb *BBox) Translate(off math32.Vector3) {
+	bb.BBox = bb.BBox.Translate(off)
+	bb.VelBBox = bb.VelBBox.Translate(off)
+	bb.OBB.Center.SetAdd(off)
+	bb.VelOBB.Center.SetAdd(off)
+	bb.BSphere.Translate(off)
 }
 
-// VelProject computes the velocity-projected bounding box for given velocity and step size
+// VelProject computes the velocity-projected bounding boxes for given velocity and step size
 func (bb *BBox) VelProject(vel math32.Vector3, step float32) {
-	eb := bb.BBox.Translate(vel.MulScalar(step))
+	disp := vel.MulScalar(step)
+	eb := bb.BBox.Translate(disp)
 	bb.VelBBox = bb.BBox
 	bb.VelBBox.ExpandByBox(eb)
+	bb.VelOBB = bb.OBB.Sweep(disp)
 }
 
-// VelNilProject is for static items -- just copy the BBox
+// VelNilProject is for static items -- just copy the BBox and OBB
 func (bb *BBox) VelNilProject() {
 	bb.VelBBox = bb.BBox
+	bb.VelOBB = bb.OBB
 }
 
 // IntersectsVelBox returns true if two velocity-projected bounding boxes intersect
 func (bb *BBox) IntersectsVelBox(oth *BBox) bool {
 	return bb.VelBBox.IntersectsBox(oth.VelBBox)
 }
+
+// IntersectsVelOBB returns true if two velocity-projected oriented bounding
+// boxes intersect, which is the midphase test for two bodies whose
+// VelBBox intersects, before the more expensive narrow phase.
+func (bb *BBox) IntersectsVelOBB(oth *BBox) bool {
+	return bb.VelOBB.Intersects(&oth.VelOBB)
+}
@@ -4,6 +4,8 @@
 
 package eve
 
+import "cogentcore.org/core/math32"
+
 // Body is the common interface for all body types
 type Body interface {
 	Node
@@ -15,6 +17,23 @@ type Body interface {
 	// It is important to collect all dynamic objects into separate top-level group(s)
 	// for more efficiently organizing the collision detection process.
 	SetDynamic() *BodyBase
+
+	// SetKinematic sets the Kinematic and Dynamic flags for this body,
+	// indicating that it is moved by script and not by physics, while still
+	// pushing movable bodies that it runs into.
+	SetKinematic() *BodyBase
+
+	// Support returns the point on the surface of the body's collision
+	// shape that is farthest along the given direction, in local body
+	// coordinates (i.e., relative to the body's own position and rotation).
+	// This is the only shape-specific function needed for narrow-phase
+	// collision detection (see ShapeDist).
+	Support(dir math32.Vector3) math32.Vector3
+
+	// LocalBBox returns the bounding box of the body's collision shape in
+	// local body coordinates, including the Abs.Scale, which SetBBox
+	// transforms into world coords.
+	LocalBBox() math32.Box3
 }
 
 // BodyBase is the base type for all specific Body types
@@ -24,11 +43,17 @@ type BodyBase struct {
 	// rigid body properties, including mass, bounce, friction etc
 	Rigid Rigid
 
-	// visualization name -- looks up an entry in the scene library that provides the visual representation of this body
+	// visualization name -- looks up an entry in the scene library that provides the visual representation of this body -- for a Proxy body, this entry is shown as is, instead of the shape of the body
 	Vis string
 
 	// default color of body for basic InitLibrary configuration
 	Color string
+
+	// rotation of a body that is moved by script over the last
+	// RelToAbs update, as an angular velocity per step, for use in the
+	// Solver -- this is kept separate from Abs.AngVel so that StepPhys
+	// does not apply the scripted rotation a second time
+	scriptAngVel math32.Vector3
 }
 
 func (bb *BodyBase) EveNodeType() NodeTypes {
@@ -51,3 +76,61 @@ func (bb *BodyBase) SetDynamic() *BodyBase {
 	bb.SetFlag(true, Dynamic)
 	return bb
 }
+
+func (bb *BodyBase) SetKinematic() *BodyBase {
+	bb.SetFlag(true, Dynamic, Kinematic)
+	return bb
+}
+
+// SetProxy sets the Proxy flag for this body, so that its shape is an
+// invisible collision proxy, and the views instead show the given Vis
+// library entry as is (which can be empty to show nothing).
+func (bb *BodyBase) SetProxy(vis string) *BodyBase {
+	bb.SetFlag(true, Proxy)
+	bb.Vis = vis
+	return bb
+}
+
+// SetNoCollide sets the NoCollide flag for this body, so that it is a
+// purely visual decoration with no collision.
+func (bb *BodyBase) SetNoCollide() *BodyBase {
+	bb.SetFlag(true, NoCollide)
+	return bb
+}
+
+// Collides returns true if the given body participates in collision,
+// i.e., it does not have the NoCollide flag.
+func Collides(bod Body) bool {
+	return !bod.AsNodeBase().Is(NoCollide)
+}
+
+// ScaledMass returns the mass of the body at its current Abs.Scale:
+// the Rigid mass is for the unscaled shape, and scales with its volume.
+func (bb *BodyBase) ScaledMass() float32 {
+	sc := bb.Abs.ScaleFactor()
+	return bb.Rigid.Mass() * sc.X * sc.Y * sc.Z
+}
+
+// ScaledInvMass returns 1 / ScaledMass -- 0 if there is no mass
+func (bb *BodyBase) ScaledInvMass() float32 {
+	sc := bb.Abs.ScaleFactor()
+	return bb.Rigid.InvMass / (sc.X * sc.Y * sc.Z)
+}
+
+// IsMovable returns true if the given body is moved by the Solver
+// in the Physics updating mode, which requires that it be Dynamic,
+// not Kinematic, and have a nonzero mass (Rigid.InvMass > 0).
+func IsMovable(bod Body) bool {
+	bb := bod.AsBodyBase()
+	return bb.IsDynamic() && !bb.IsKinematic() && bb.Rigid.InvMass > 0
+}
+
+// Support for the base body is a single point at its center
+func (bb *BodyBase) Support(dir math32.Vector3) math32.Vector3 {
+	return math32.Vector3{}
+}
+
+// LocalBBox for the base body is a single point at its center
+func (bb *BodyBase) LocalBBox() math32.Box3 {
+	return math32.Box3{}
+}
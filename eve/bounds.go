@@ -0,0 +1,344 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package eve
+
+import (
+	"sort"
+
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tree"
+)
+
+// BoundsModes are the ways that the motion of objects is bounded along
+// each axis of a Bounds Region
+type BoundsModes int32 //enums:enum
+
+const (
+	// NoBounds does not bound motion along this axis
+	NoBounds BoundsModes = iota
+
+	// BoundsReport reports objects whose center is outside of the Region
+	// along this axis in Bounds.Out, without affecting their motion,
+	// e.g., to detect agents that have escaped from a room.
+	BoundsReport
+
+	// BoundsClamp keeps objects entirely within the Region along this
+	// axis, as if by invisible walls, stopping any motion out of it,
+	// and reports the objects that were moved back in Bounds.Out.
+	BoundsClamp
+
+	// BoundsWrap wraps the world around along this axis (toroidal):
+	// objects whose center leaves the Region across one edge re-enter
+	// across the opposite edge, and bodies near opposite edges collide
+	// with each other across the wrap.
+	BoundsWrap
+)
+
+// Bounds bounds the motion of the objects in a world to a Region, with
+// a separate BoundsModes for each axis, e.g., wrapping around on the X
+// and Z axes for an open field without walls.  The objects are the
+// movable bodies (see IsMovable), and the Dynamic groups and bodies
+// that are moved by script and do not contain any movable bodies
+// (e.g., an agent), which are moved as a whole.  Soft bodies and
+// Particles are not bounded.  Apply is called by Solver.Step
+// after movable bodies are stepped.
+// Only collisions (SpatialHash) and RayBodyIntersections wrap around:
+// the other queries on the world, such as BodiesInBox, the Overlap
+// queries and NearestBody, only find bodies at their positions within
+// the Region, so they must be repeated with an offset of the Region
+// Size to find bodies across the edges.
+type Bounds struct {
+
+	// region of the world in world coords
+	Region math32.Box3
+
+	// how motion is bounded along the X axis
+	X BoundsModes
+
+	// how motion is bounded along the Y axis
+	Y BoundsModes
+
+	// how motion is bounded along the Z axis
+	Z BoundsModes
+
+	// objects that were outside of the Region on a BoundsReport or BoundsClamp axis on the last Apply
+	Out []Node `display:"-"`
+}
+
+// Mode returns the BoundsModes for given axis
+func (bd *Bounds) Mode(dim math32.Dims) BoundsModes {
+	switch dim {
+	case math32.X:
+		return bd.X
+	case math32.Y:
+		return bd.Y
+	default:
+		return bd.Z
+	}
+}
+
+// SetWrap sets the given region, wrapping around on the X and Z axes,
+// e.g., for an open field
+func (bd *Bounds) SetWrap(region math32.Box3) *Bounds {
+	bd.Region = region
+	bd.X, bd.Z = BoundsWrap, BoundsWrap
+	return bd
+}
+
+// On returns true if any axis is bounded
+func (bd *Bounds) On() bool {
+	return bd.X != NoBounds || bd.Y != NoBounds || bd.Z != NoBounds
+}
+
+// Wraps returns true if any axis wraps around
+func (bd *Bounds) Wraps() bool {
+	return bd.wraps(math32.X) || bd.wraps(math32.Y) || bd.wraps(math32.Z)
+}
+
+// wraps returns true if the given axis wraps around, with a valid Region
+func (bd *Bounds) wraps(dim math32.Dims) bool {
+	return bd.Mode(dim) == BoundsWrap && bd.Region.Max.Dim(dim) > bd.Region.Min.Dim(dim)
+}
+
+// Size returns the size of the Region
+func (bd *Bounds) Size() math32.Vector3 {
+	return bd.Region.Size()
+}
+
+// Wrap returns the given point wrapped into the Region on the wrapped axes
+func (bd *Bounds) Wrap(p math32.Vector3) math32.Vector3 {
+	for d := math32.X; d <= math32.Z; d++ {
+		if !bd.wraps(d) {
+			continue
+		}
+		mn, sz := bd.Region.Min.Dim(d), bd.Region.Max.Dim(d)-bd.Region.Min.Dim(d)
+		p.SetDim(d, p.Dim(d)-sz*math32.Floor((p.Dim(d)-mn)/sz))
+	}
+	return p
+}
+
+// Delta returns the shortest displacement from point b to point a, going
+// across the edges of the Region on the wrapped axes where that is shorter,
+// e.g., for the direction and distance from an agent to a target.
+func (bd *Bounds) Delta(a, b math32.Vector3) math32.Vector3 {
+	d := a.Sub(b)
+	for dm := math32.X; dm <= math32.Z; dm++ {
+		if !bd.wraps(dm) {
+			continue
+		}
+		sz := bd.Region.Max.Dim(dm) - bd.Region.Min.Dim(dm)
+		d.SetDim(dm, d.Dim(dm)-sz*math32.Round(d.Dim(dm)/sz))
+	}
+	return d
+}
+
+// Distance returns the shortest distance between given points (see Delta)
+func (bd *Bounds) Distance(a, b math32.Vector3) float32 {
+	return bd.Delta(a, b).Length()
+}
+
+// images calls fun with each offset of the Region, as a multiple of
+// its size on the wrapped axes, from -1 to 1, except for no offset
+func (bd *Bounds) images(fun func(off math32.Vector3)) {
+	sz := bd.Size()
+	var rng [3]int
+	for d := math32.X; d <= math32.Z; d++ {
+		if bd.wraps(d) {
+			rng[d] = 1
+		}
+	}
+	for x := -rng[0]; x <= rng[0]; x++ {
+		for y := -rng[1]; y <= rng[1]; y++ {
+			for z := -rng[2]; z <= rng[2]; z++ {
+				if x == 0 && y == 0 && z == 0 {
+					continue
+				}
+				fun(math32.Vec3(float32(x)*sz.X, float32(y)*sz.Y, float32(z)*sz.Z))
+			}
+		}
+	}
+}
+
+// atEdge returns true if given box reaches the edges of the Region, to
+// within a small tolerance, on any of the wrapped axes along which given
+// offset is nonzero
+func (bd *Bounds) atEdge(box math32.Box3, off math32.Vector3) bool {
+	for d := math32.X; d <= math32.Z; d++ {
+		if off.Dim(d) == 0 {
+			continue
+		}
+		mn, mx := bd.Region.Min.Dim(d), bd.Region.Max.Dim(d)
+		tol := 1.0e-3 * (mx - mn)
+		if box.Min.Dim(d) <= mn+tol || box.Max.Dim(d) >= mx-tol {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply applies the bounds to the objects in given world, reporting,
+// clamping and wrapping them according to the mode of each axis, and
+// updating the group bounding boxes.  The velocity of movable bodies
+// out of the Region on a BoundsClamp axis is stopped.
+func (bd *Bounds) Apply(world *Group) {
+	bd.Out = bd.Out[:0]
+	if !bd.On() {
+		return
+	}
+	root := world.This()
+	world.WalkDown(func(k tree.Node) bool {
+		nii, ni := AsNode(k)
+		if nii == nil {
+			return false // going into a different type of thing, bail
+		}
+		if k.This() == root {
+			return true
+		}
+		if !nii.IsDynamic() {
+			return false
+		}
+		switch nii.EveNodeType() {
+		case BODY:
+		case GROUP:
+			if hasMovable(nii) {
+				return true
+			}
+		default:
+			return false
+		}
+		bd.applyNode(nii, ni)
+		return false
+	})
+	world.WorldDynGroupBBox()
+}
+
+// applyNode applies the bounds to given object
+func (bd *Bounds) applyNode(nii Node, ni *NodeBase) {
+	box := ni.BBox.BBox
+	if box.IsEmpty() {
+		return
+	}
+	pos := ni.Abs.Pos
+	if nii.EveNodeType() == GROUP {
+		pos = box.Center()
+	}
+	var off math32.Vector3
+	out := false
+	for d := math32.X; d <= math32.Z; d++ {
+		mn, mx := bd.Region.Min.Dim(d), bd.Region.Max.Dim(d)
+		p := pos.Dim(d)
+		switch bd.Mode(d) {
+		case BoundsReport:
+			if p < mn || p > mx {
+				out = true
+			}
+		case BoundsClamp:
+			switch {
+			case box.Min.Dim(d) < mn:
+				off.SetDim(d, mn-box.Min.Dim(d))
+				out = true
+			case box.Max.Dim(d) > mx:
+				off.SetDim(d, mx-box.Max.Dim(d))
+				out = true
+			}
+		case BoundsWrap:
+			if mx <= mn {
+				continue
+			}
+			if p < mn || p >= mx {
+				off.SetDim(d, -(mx-mn)*math32.Floor((p-mn)/(mx-mn)))
+			}
+		}
+	}
+	if out {
+		bd.Out = append(bd.Out, nii)
+	}
+	if off == (math32.Vector3{}) {
+		return
+	}
+	ni.shiftAbs(off)
+	if bod := nii.AsBody(); bod != nil && IsMovable(bod) {
+		for d := math32.X; d <= math32.Z; d++ {
+			o := off.Dim(d)
+			if bd.Mode(d) == BoundsClamp && o*ni.Abs.LinVel.Dim(d) < 0 {
+				ni.Abs.LinVel.SetDim(d, 0)
+			}
+		}
+	}
+}
+
+// hasMovable returns true if the given node contains any movable bodies
+func hasMovable(n Node) bool {
+	has := false
+	n.WalkDown(func(k tree.Node) bool {
+		nii, _ := AsNode(k)
+		if nii == nil || has {
+			return false
+		}
+		if bod := nii.AsBody(); bod != nil {
+			has = IsMovable(bod)
+			return false
+		}
+		return true
+	})
+	return has
+}
+
+// RayBodyIntersections returns a list of bodies in given world whose
+// bounding box intersects with the given ray within maxDist of its origin
+// (with a unit-length Dir), with the point of intersection, as in
+// Group.RayBodyIntersections, sorted by distance along the ray.
+// On the wrapped axes, the ray continues across the edges of the Region,
+// so that bodies can be hit on the other side, or even multiple times,
+// with the points in the world coords of the bodies.  Static bodies that
+// extend beyond the Region along a wrapped axis (e.g., a floor that is
+// larger than it) can be hit in each copy of the Region that the ray
+// passes through.
+func (bd *Bounds) RayBodyIntersections(world *Group, ray math32.Ray, maxDist float32) []*BodyPoint {
+	type hit struct {
+		bp   *BodyPoint
+		dist float32
+	}
+	var hits []hit
+	seg := math32.B3Empty()
+	seg.ExpandByPoint(ray.Origin)
+	seg.ExpandByPoint(ray.Origin.Add(ray.Dir.MulScalar(maxDist)))
+	wb := world.BBox.BBox
+	if world.Shared != nil {
+		wb.ExpandByBox(world.Shared.BBox.BBox)
+	}
+	sz := bd.Size()
+	var lo, hi [3]int
+	for d := math32.X; d <= math32.Z; d++ {
+		if !bd.wraps(d) {
+			continue
+		}
+		s := sz.Dim(d)
+		lo[d] = int(math32.Ceil((seg.Min.Dim(d) - wb.Max.Dim(d)) / s))
+		hi[d] = int(math32.Floor((seg.Max.Dim(d) - wb.Min.Dim(d)) / s))
+	}
+	for x := lo[0]; x <= hi[0]; x++ {
+		for y := lo[1]; y <= hi[1]; y++ {
+			for z := lo[2]; z <= hi[2]; z++ {
+				off := math32.Vec3(float32(x)*sz.X, float32(y)*sz.Y, float32(z)*sz.Z)
+				r := ray
+				r.Origin = ray.Origin.Sub(off)
+				for _, bp := range world.RayBodyIntersections(r) {
+					if dist := bp.Point.DistanceTo(r.Origin); dist <= maxDist {
+						hits = append(hits, hit{bp, dist})
+					}
+				}
+			}
+		}
+	}
+	sort.SliceStable(hits, func(i, j int) bool {
+		return hits[i].dist < hits[j].dist
+	})
+	bs := make([]*BodyPoint, len(hits))
+	for i, h := range hits {
+		bs[i] = h.bp
+	}
+	return bs
+}
@@ -0,0 +1,40 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package eve
+
+import (
+	"testing"
+
+	"cogentcore.org/core/math32"
+)
+
+func TestRayWrapShared(t *testing.T) {
+	shared := &Group{}
+	shared.InitName(shared, "shared")
+	newTestBox(shared, "wall", math32.Vec3(4.5, 0, 0), math32.Vec3(0.4, 2, 2))
+	shared.WorldInit()
+
+	w := newTestWorld()
+	w.Shared = shared
+	newTestBox(w, "agent", math32.Vec3(0, 0, 0), math32.Vec3(1, 1, 1)).SetDynamic()
+	w.WorldInit()
+
+	var bd Bounds
+	bd.Region = math32.B3(-5, -5, -5, 5, 5, 5)
+	bd.X = BoundsWrap
+	ray := math32.NewRay(math32.Vec3(0, 0, 0), math32.Vec3(-1, 0, 0))
+	var hit *BodyPoint
+	for _, bp := range bd.RayBodyIntersections(w, *ray, 8) {
+		if bp.Body.AsNodeBase().Name() == "wall" {
+			hit = bp
+		}
+	}
+	if hit == nil {
+		t.Fatal("ray across the edge of the Region did not hit the shared wall")
+	}
+	if math32.Abs(hit.Point.X-4.7) > 1.0e-4 {
+		t.Errorf("hit the shared wall at %v, want X = 4.7", hit.Point)
+	}
+}
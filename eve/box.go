@@ -17,18 +17,40 @@ type Box struct {
 }
 
 func (bx *Box) SetBBox() {
-	bx.BBox.SetBounds(bx.Size.MulScalar(-.5), bx.Size.MulScalar(.5))
+	lb := bx.LocalBBox()
+	bx.BBox.SetBounds(lb.Min, lb.Max)
 	bx.BBox.XForm(bx.Abs.Quat, bx.Abs.Pos)
 }
 
+func (bx *Box) LocalBBox() math32.Box3 {
+	hs := bx.Size.MulScalar(.5).Mul(bx.Abs.ScaleFactor())
+	return math32.Box3{Min: hs.Negate(), Max: hs}
+}
+
+func (bx *Box) Support(dir math32.Vector3) math32.Vector3 {
+	hs := bx.Size.MulScalar(.5)
+	return math32.Vec3(math32.Copysign(hs.X, dir.X), math32.Copysign(hs.Y, dir.Y), math32.Copysign(hs.Z, dir.Z))
+}
+
+// SetInertia sets the Rigid.RotInertia for a solid box of the current
+// ScaledMass and scaled size
+func (bx *Box) SetInertia() {
+	m := bx.ScaledMass() / 12
+	sz := bx.Size.Mul(bx.Abs.ScaleFactor())
+	sq := sz.Mul(sz)
+	bx.Rigid.SetShapeInertia(math32.Vec3(m*(sq.Y+sq.Z), m*(sq.X+sq.Z), m*(sq.X+sq.Y)))
+}
+
 func (bx *Box) InitAbs(par *NodeBase) {
 	bx.InitAbsBase(par)
+	bx.SetInertia()
 	bx.SetBBox()
 	bx.BBox.VelNilProject()
 }
 
 func (bx *Box) RelToAbs(par *NodeBase) {
 	bx.RelToAbsBase(par)
+	bx.SetInertia()
 	bx.SetBBox()
 	bx.BBox.VelProject(bx.Abs.LinVel, 1)
 }
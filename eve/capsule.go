@@ -24,20 +24,53 @@ type Capsule struct {
 }
 
 func (cp *Capsule) SetBBox() {
+	lb := cp.LocalBBox()
+	cp.BBox.SetBounds(lb.Min, lb.Max)
+	cp.BBox.XForm(cp.Abs.Quat, cp.Abs.Pos)
+}
+
+func (cp *Capsule) LocalBBox() math32.Box3 {
 	th := cp.Height + cp.TopRad + cp.BotRad
 	h2 := th / 2
-	cp.BBox.SetBounds(math32.Vec3(-cp.BotRad, -h2, -cp.BotRad), math32.Vec3(cp.TopRad, h2, cp.TopRad))
-	cp.BBox.XForm(cp.Abs.Quat, cp.Abs.Pos)
+	r := max(cp.TopRad, cp.BotRad)
+	ext := math32.Vec3(r, h2, r).Mul(cp.Abs.ScaleFactor())
+	return math32.Box3{Min: ext.Negate(), Max: ext}
+}
+
+func (cp *Capsule) Support(dir math32.Vector3) math32.Vector3 {
+	th := cp.Height + cp.TopRad + cp.BotRad
+	h2 := th / 2
+	l := dir.Length()
+	if l == 0 {
+		return math32.Vec3(0, h2, 0)
+	}
+	nd := dir.DivScalar(l)
+	top := math32.Vec3(0, h2-cp.TopRad, 0).Add(nd.MulScalar(cp.TopRad))
+	bot := math32.Vec3(0, cp.BotRad-h2, 0).Add(nd.MulScalar(cp.BotRad))
+	if top.Dot(dir) >= bot.Dot(dir) {
+		return top
+	}
+	return bot
+}
+
+// SetInertia sets the Rigid.RotInertia for the current ScaledMass,
+// approximating the capsule as a cylinder of the full height,
+// using the average of the top and bottom radii
+func (cp *Capsule) SetInertia() {
+	th := cp.Height + cp.TopRad + cp.BotRad
+	cp.Rigid.SetShapeInertia(cylinderInertia(cp.ScaledMass(), 0.5*(cp.TopRad+cp.BotRad), th, cp.Abs.ScaleFactor()))
 }
 
 func (cp *Capsule) InitAbs(par *NodeBase) {
 	cp.InitAbsBase(par)
+	cp.SetInertia()
 	cp.SetBBox()
 	cp.BBox.VelNilProject()
 }
 
 func (cp *Capsule) RelToAbs(par *NodeBase) {
 	cp.RelToAbsBase(par)
+	cp.SetInertia()
 	cp.SetBBox()
 	cp.BBox.VelProject(cp.Abs.LinVel, 1)
 }
@@ -0,0 +1,288 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package eve
+
+import (
+	"cogentcore.org/core/math32"
+)
+
+// Character is a kinematic character controller, which moves a scripted
+// object (e.g., an agent) through the world the way that characters move
+// in games: a single call to Move resolves any penetrations, slides along
+// walls, steps up over small ledges, and reports whether the character
+// is Grounded or Blocked.  The collision shape of the character is the
+// given Body, typically a Capsule or Box, and the Root node that is moved
+// can be that body, or a Group that contains it along with other bodies
+// (e.g., a head with eyes), which are not treated as obstacles.
+// The Root is moved by updating its Rel.Pos, so WorldRelToAbs (or
+// Solver.Step) must be called after Move, as usual for scripted updates.
+// Up is always the Y axis.  Move does not apply gravity -- include a
+// downward component in the desired motion when not Grounded.
+// When Grounded on a body that is moved by script (e.g., a Kinematic
+// platform, elevator or turntable), the character is carried along by
+// its motion if Ride is set.
+type Character struct {
+
+	// the world containing the character and the obstacles
+	World *Group
+
+	// the node that is moved by the controller: the Body or a Group containing it
+	Root Node
+
+	// the body whose collision shape is used for the character
+	Body Body
+
+	// maximum height of ledges that the character can step up onto
+	StepHeight float32 `default:"0.3"`
+
+	// maximum slope in degrees that the character can walk up -- steeper surfaces are treated as walls
+	MaxSlope float32 `default:"45"`
+
+	// distance that is maintained between the character and obstacles, to avoid getting stuck due to numerical error
+	SkinWidth float32 `default:"0.01"`
+
+	// maximum number of times the motion is deflected along surfaces in each Move
+	MaxSlides int `default:"4"`
+
+	// whether the character is carried along by the scripted motion of the Ground that it is standing on, in position only (its rotation is not changed)
+	Ride bool `default:"true"`
+
+	// true if the character is standing on a walkable surface after the last Move
+	Grounded bool `edit:"-"`
+
+	// true if the horizontal motion of the last Move was blocked by a wall
+	Blocked bool `edit:"-"`
+
+	// normal of the ground surface when Grounded
+	GroundNormal math32.Vector3 `edit:"-"`
+
+	// the body that the character is standing on when Grounded
+	Ground Body `edit:"-"`
+
+	// normal of the wall that blocked the character, when Blocked
+	WallNormal math32.Vector3 `edit:"-"`
+
+	// obstacles near the character, collected at the start of Move
+	obs []Body
+}
+
+// NewCharacter returns a new Character for given world, root node and body,
+// with default parameters
+func NewCharacter(world *Group, root Node, body Body) *Character {
+	ch := &Character{World: world, Root: root, Body: body}
+	ch.Defaults()
+	return ch
+}
+
+func (ch *Character) Defaults() {
+	ch.StepHeight = 0.3
+	ch.MaxSlope = 45
+	ch.SkinWidth = 0.01
+	ch.MaxSlides = 4
+	ch.Ride = true
+}
+
+// Move moves the character by the desired displacement in world coords,
+// as far as possible given the obstacles in the world, and returns the
+// actual displacement.  The horizontal and vertical components of the
+// motion are applied separately, with the horizontal motion stepping
+// up onto ledges up to StepHeight, and sliding along walls.
+// Any motion of the Ground since the last Move is applied first (see Ride),
+// and is included in the returned displacement.
+func (ch *Character) Move(desired math32.Vector3) math32.Vector3 {
+	ch.Blocked = false
+	ch.WallNormal = math32.Vector3{}
+	carry := ch.groundMotion()
+	ch.collectObstacles(desired.Add(carry))
+	off := ch.depenetrate(carry)
+
+	horiz := math32.Vec3(desired.X, 0, desired.Z)
+	if horiz.LengthSquared() > 0 {
+		plain, blocked, wn := ch.slide(off, horiz, true)
+		if blocked && ch.StepHeight > 0 {
+			if stepped, ok := ch.stepUp(off, horiz); ok && stepped.Sub(off).LengthSquared() > plain.Sub(off).LengthSquared()+1.0e-8 {
+				plain, blocked = stepped, false
+			}
+		}
+		off = plain
+		ch.Blocked = blocked
+		if blocked {
+			ch.WallNormal = wn
+		}
+	}
+	if desired.Y != 0 {
+		off, _, _ = ch.slide(off, math32.Vec3(0, desired.Y, 0), false)
+	}
+	ch.probeGround(off, 2*ch.SkinWidth)
+	ch.Root.AsNodeBase().addRelPos(off)
+	return off
+}
+
+// groundMotion returns the displacement of the character from the scripted
+// motion of its Ground in the last update, including rotation about the
+// center of the Ground, if Ride is set.  Ground bodies that are static,
+// or moved by physics, do not carry the character.
+func (ch *Character) groundMotion() math32.Vector3 {
+	if !ch.Ride || !ch.Grounded || ch.Ground == nil {
+		return math32.Vector3{}
+	}
+	gb := ch.Ground.AsBodyBase()
+	if !gb.IsDynamic() || IsMovable(ch.Ground) {
+		return math32.Vector3{}
+	}
+	pos := ch.Body.AsNodeBase().Abs.Pos
+	prv := gb.Abs.Pos.Sub(gb.Abs.LinVel)
+	rel := pos.Sub(prv)
+	if ang := gb.scriptAngVel.Length(); ang > 0 {
+		rel = rel.MulQuat(math32.NewQuatAxisAngle(gb.scriptAngVel.DivScalar(ang), ang))
+	}
+	return gb.Abs.Pos.Add(rel).Sub(pos)
+}
+
+// slopeCos returns the cosine of MaxSlope
+func (ch *Character) slopeCos() float32 {
+	return math32.Cos(math32.DegToRad(ch.MaxSlope))
+}
+
+// walkable returns true if the surface with given normal can be walked on
+func (ch *Character) walkable(n math32.Vector3) bool {
+	return n.Y >= ch.slopeCos()
+}
+
+// pose returns the shapePose of the character body at given offset from its current position
+func (ch *Character) pose(off math32.Vector3) shapePose {
+	sp := bodyPose(ch.Body)
+	sp.pos.SetAdd(off)
+	return sp
+}
+
+// collectObstacles collects the bodies whose bounding boxes are within
+// reach of the character for given desired motion
+func (ch *Character) collectObstacles(desired math32.Vector3) {
+	bb := ch.Body.AsNodeBase().BBox.BBox
+	ext := desired.Abs().AddScalar(ch.StepHeight + 4*ch.SkinWidth)
+	bb.Min.SetSub(ext)
+	bb.Max.SetAdd(ext)
+	ch.obs = ch.World.BodiesInBox(bb, ch.Root)
+}
+
+// cast finds the first obstacle hit by the character moving from given
+// offset by given motion, returning the fraction t of the motion before
+// the hit, and the normal of the obstacle surface, pointing toward the character.
+func (ch *Character) cast(off, motion math32.Vector3) (t float32, norm math32.Vector3, hit Body) {
+	t = 1
+	sp := ch.pose(off)
+	for _, ob := range ch.obs {
+		op := bodyPose(ob)
+		if ht, n, ok := shapeCast(&sp, motion, &op, ch.SkinWidth); ok && ht < t {
+			t, norm, hit = ht, n, ob
+		}
+	}
+	return
+}
+
+// slide moves the character from given offset by given motion, deflecting
+// the motion along each surface that is hit, up to MaxSlides times, and
+// returns the resulting offset.  If horiz, then surfaces that are not
+// walkable are treated as vertical walls, and blocked is returned as true
+// if any such wall was hit, along with its normal.
+func (ch *Character) slide(off, motion math32.Vector3, horiz bool) (math32.Vector3, bool, math32.Vector3) {
+	blocked := false
+	var wn math32.Vector3
+	dir := motion
+	for i := 0; i < ch.MaxSlides && motion.LengthSquared() > 1.0e-12; i++ {
+		t, n, hit := ch.cast(off, motion)
+		off.SetAdd(motion.MulScalar(t))
+		if hit == nil {
+			break
+		}
+		if horiz && !ch.walkable(n) {
+			blocked = true
+			n.Y = 0
+			if n.LengthSquared() < 1.0e-12 {
+				break
+			}
+			n.SetNormal()
+			wn = n
+		}
+		motion = motion.MulScalar(1 - t)
+		if d := motion.Dot(n); d < 0 {
+			motion.SetSub(n.MulScalar(d))
+		}
+		if motion.Dot(dir) <= 0 {
+			break // do not move backward
+		}
+	}
+	return off, blocked, wn
+}
+
+// stepUp tries to move the character horizontally from given offset after
+// first moving it up by StepHeight, and then back down onto a walkable
+// surface, returning the resulting offset and true if successful.
+func (ch *Character) stepUp(off, horiz math32.Vector3) (math32.Vector3, bool) {
+	up := math32.Vec3(0, ch.StepHeight, 0)
+	t, _, _ := ch.cast(off, up)
+	rise := up.MulScalar(t)
+	soff, _, _ := ch.slide(off.Add(rise), horiz, true)
+	down := math32.Vec3(0, -(rise.Y + ch.SkinWidth), 0)
+	dt, n, hit := ch.cast(soff, down)
+	if hit == nil || !ch.walkable(n) {
+		return off, false
+	}
+	return soff.Add(down.MulScalar(dt)), true
+}
+
+// depenetrate pushes the character at given offset out of any obstacles
+// that it overlaps, returning the resulting offset
+func (ch *Character) depenetrate(off math32.Vector3) math32.Vector3 {
+	sep, _ := separation(ch.pose(off), ch.obs, 0.5*ch.SkinWidth, 4)
+	return off.Add(sep)
+}
+
+// probeGround checks for a walkable surface within given distance below
+// the character at given offset, setting Grounded, GroundNormal and Ground
+func (ch *Character) probeGround(off math32.Vector3, dist float32) {
+	ch.Grounded = false
+	ch.Ground = nil
+	ch.GroundNormal = math32.Vector3{}
+	_, n, hit := ch.cast(off, math32.Vec3(0, -dist, 0))
+	if hit != nil && ch.walkable(n) {
+		ch.Grounded = true
+		ch.Ground = hit
+		ch.GroundNormal = n
+	}
+}
+
+// shapeCast returns the fraction t of the given motion of shape a at
+// which it comes within skin distance of shape b, using conservative
+// advancement, along with the normal pointing from b toward a at that
+// point, and true if there is such a hit within the motion.  If the shapes
+// are already within skin distance, then it is a hit at t = 0 only if
+// the motion is toward b.
+func shapeCast(a *shapePose, motion math32.Vector3, b *shapePose, skin float32) (float32, math32.Vector3, bool) {
+	pa := *a
+	var t float32
+	var n math32.Vector3
+	for iter := 0; iter < gjkMaxIters; iter++ {
+		var d float32
+		d, _, _, n = shapeDist(&pa, b)
+		closing := -motion.Dot(n)
+		if d <= skin {
+			if iter == 0 && closing <= 0 {
+				return 0, n, false
+			}
+			return t, n, true
+		}
+		if closing <= 1.0e-9 {
+			return 0, n, false // moving away: distance only increases for convex shapes
+		}
+		t += (d - 0.5*skin) / closing
+		if t > 1 {
+			return 0, n, false
+		}
+		pa.pos = a.pos.Add(motion.MulScalar(t))
+	}
+	return t, n, true // not converged: stop here to be safe
+}
@@ -10,8 +10,8 @@ import (
 )
 
 // Contact is one pairwise point of contact between two bodies.
-// Contacts are represented in spherical terms relative to the
-// spherical BBox of A and B.
+// The contact geometry is computed by UpdtDist using the narrow-phase
+// ShapeDist computation on the collision shapes of A and B.
 type Contact struct {
 
 	// one body
@@ -20,19 +20,39 @@ type Contact struct {
 	// the other body
 	B Body
 
-	// normal pointing from center of B to center of A
+	// normal pointing from B to A, which is the direction to move A to separate it from B
 	NormB math32.Vector3
 
-	// point on spherical shell of B where A is contacting
+	// point on the surface of B closest to A (or deepest within A, if overlapping)
 	PtB math32.Vector3
 
-	// distance from PtB along NormB to contact point on spherical shell of A
+	// signed distance from PtB along NormB to the contact point on the surface of A: negative if the bodies overlap, by the penetration depth
 	Dist float32
+
+	// offset of B from its position in the world, for a contact across the edges of a world that wraps around (see Bounds), in which case PtB is also offset
+	Offset math32.Vector3
+
+	// combined surface properties for this contact, from the Materials of the two bodies
+	Surface
 }
 
 // UpdtDist updates the distance information for the contact
 func (c *Contact) UpdtDist() {
+	pa := bodyPose(c.A)
+	pb := bodyPose(c.B)
+	pb.pos.SetAdd(c.Offset)
+	c.Dist, _, c.PtB, c.NormB = shapeDist(&pa, &pb)
+}
 
+// PtA returns the point on the surface of A closest to B (or deepest within B)
+func (c *Contact) PtA() math32.Vector3 {
+	return c.PtB.Add(c.NormB.MulScalar(c.Dist))
+}
+
+// SetMaterials sets the combined Surface properties for the contact
+// from the given Materials table
+func (c *Contact) SetMaterials(mt *Materials) {
+	c.Surface = mt.Combine(&c.A.AsBodyBase().Rigid, &c.B.AsBodyBase().Rigid)
 }
 
 // Contacts is a slice list of contacts
@@ -47,7 +67,8 @@ func (cs *Contacts) New(a, b Body) *Contact {
 
 // BodyVelBBoxIntersects returns the list of potential contact nodes between a and b
 // (could be the same or different groups) that have intersecting velocity-projected
-// bounding boxes.  In general a should be dynamic bodies and b either dynamic or static.
+// bounding boxes, and then velocity-projected oriented bounding boxes (midphase).
+// In general a should be dynamic bodies and b either dynamic or static.
 // This is the broad first-pass filtering.
 func BodyVelBBoxIntersects(a, b Node) Contacts {
 	var cts Contacts
@@ -60,6 +81,9 @@ func BodyVelBBoxIntersects(a, b Node) Contacts {
 			return true
 		}
 		abod := aii.AsBody() // only consider bodies from a
+		if !Collides(abod) {
+			return false
+		}
 
 		b.WalkDown(func(k tree.Node) bool {
 			bii, bi := AsNode(k)
@@ -70,7 +94,9 @@ func BodyVelBBoxIntersects(a, b Node) Contacts {
 				return false // done
 			}
 			if bii.EveNodeType() == BODY {
-				cts.New(abod, bii.AsBody())
+				if Collides(bii.AsBody()) && ai.BBox.IntersectsVelOBB(&bi.BBox) { // midphase
+					cts.New(abod, bii.AsBody())
+				}
 				return false // done
 			}
 			return true // keep going
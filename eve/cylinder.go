@@ -24,19 +24,65 @@ type Cylinder struct {
 }
 
 func (cy *Cylinder) SetBBox() {
-	h2 := cy.Height / 2
-	cy.BBox.SetBounds(math32.Vec3(-cy.BotRad, -h2, -cy.BotRad), math32.Vec3(cy.TopRad, h2, cy.TopRad))
+	lb := cy.LocalBBox()
+	cy.BBox.SetBounds(lb.Min, lb.Max)
 	cy.BBox.XForm(cy.Abs.Quat, cy.Abs.Pos)
 }
 
+func (cy *Cylinder) LocalBBox() math32.Box3 {
+	h2 := cy.Height / 2
+	r := max(cy.TopRad, cy.BotRad)
+	ext := math32.Vec3(r, h2, r).Mul(cy.Abs.ScaleFactor())
+	return math32.Box3{Min: ext.Negate(), Max: ext}
+}
+
+func (cy *Cylinder) Support(dir math32.Vector3) math32.Vector3 {
+	h2 := cy.Height / 2
+	top := discSupport(dir, cy.TopRad, h2)
+	bot := discSupport(dir, cy.BotRad, -h2)
+	if top.Dot(dir) >= bot.Dot(dir) {
+		return top
+	}
+	return bot
+}
+
+// discSupport returns the support point along dir of a horizontal disc
+// of given radius at height y on the Y axis
+func discSupport(dir math32.Vector3, rad, y float32) math32.Vector3 {
+	l := math32.Sqrt(dir.X*dir.X + dir.Z*dir.Z)
+	if l == 0 {
+		return math32.Vec3(0, y, 0)
+	}
+	s := rad / l
+	return math32.Vec3(dir.X*s, y, dir.Z*s)
+}
+
+// SetInertia sets the Rigid.RotInertia for a solid cylinder of the current
+// ScaledMass, using the average of the top and bottom radii
+func (cy *Cylinder) SetInertia() {
+	cy.Rigid.SetShapeInertia(cylinderInertia(cy.ScaledMass(), 0.5*(cy.TopRad+cy.BotRad), cy.Height, cy.Abs.ScaleFactor()))
+}
+
+// cylinderInertia returns the principal moments of inertia of a solid
+// cylinder along the Y axis with given mass, radius and height, scaled by
+// given factors, which make it an elliptic cylinder for unequal X, Z scales
+func cylinderInertia(m, r, h float32, sc math32.Vector3) math32.Vector3 {
+	a := r * sc.X
+	c := r * sc.Z
+	hh := h * sc.Y
+	return math32.Vec3(m*(3*c*c+hh*hh)/12, m*(a*a+c*c)/4, m*(3*a*a+hh*hh)/12)
+}
+
 func (cy *Cylinder) InitAbs(par *NodeBase) {
 	cy.InitAbsBase(par)
+	cy.SetInertia()
 	cy.SetBBox()
 	cy.BBox.VelNilProject()
 }
 
 func (cy *Cylinder) RelToAbs(par *NodeBase) {
 	cy.RelToAbsBase(par)
+	cy.SetInertia()
 	cy.SetBBox()
 	cy.BBox.VelProject(cy.Abs.LinVel, 1)
 }
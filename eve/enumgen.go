@@ -7,16 +7,102 @@ import (
 	"cogentcore.org/core/tree"
 )
 
-var _NodeTypesValues = []NodeTypes{0, 1, 2}
+var _BoundsModesValues = []BoundsModes{0, 1, 2, 3}
+
+// BoundsModesN is the highest valid value for type BoundsModes, plus one.
+const BoundsModesN BoundsModes = 4
+
+var _BoundsModesValueMap = map[string]BoundsModes{`NoBounds`: 0, `BoundsReport`: 1, `BoundsClamp`: 2, `BoundsWrap`: 3}
+
+var _BoundsModesDescMap = map[BoundsModes]string{0: `NoBounds does not bound motion along this axis`, 1: `BoundsReport reports objects whose center is outside of the Region along this axis in Bounds.Out, without affecting their motion, e.g., to detect agents that have escaped from a room.`, 2: `BoundsClamp keeps objects entirely within the Region along this axis, as if by invisible walls, stopping any motion out of it, and reports the objects that were moved back in Bounds.Out.`, 3: `BoundsWrap wraps the world around along this axis (toroidal): objects whose center leaves the Region across one edge re-enter across the opposite edge, and bodies near opposite edges collide with each other across the wrap.`}
+
+var _BoundsModesMap = map[BoundsModes]string{0: `NoBounds`, 1: `BoundsReport`, 2: `BoundsClamp`, 3: `BoundsWrap`}
+
+// String returns the string representation of this BoundsModes value.
+func (i BoundsModes) String() string { return enums.String(i, _BoundsModesMap) }
+
+// SetString sets the BoundsModes value from its string representation,
+// and returns an error if the string is invalid.
+func (i *BoundsModes) SetString(s string) error {
+	return enums.SetString(i, s, _BoundsModesValueMap, "BoundsModes")
+}
+
+// Int64 returns the BoundsModes value as an int64.
+func (i BoundsModes) Int64() int64 { return int64(i) }
+
+// SetInt64 sets the BoundsModes value from an int64.
+func (i *BoundsModes) SetInt64(in int64) { *i = BoundsModes(in) }
+
+// Desc returns the description of the BoundsModes value.
+func (i BoundsModes) Desc() string { return enums.Desc(i, _BoundsModesDescMap) }
+
+// BoundsModesValues returns all possible values for the type BoundsModes.
+func BoundsModesValues() []BoundsModes { return _BoundsModesValues }
+
+// Values returns all possible values for the type BoundsModes.
+func (i BoundsModes) Values() []enums.Enum { return enums.Values(_BoundsModesValues) }
+
+// MarshalText implements the [encoding.TextMarshaler] interface.
+func (i BoundsModes) MarshalText() ([]byte, error) { return []byte(i.String()), nil }
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (i *BoundsModes) UnmarshalText(text []byte) error {
+	return enums.UnmarshalText(i, text, "BoundsModes")
+}
+
+var _CombineModesValues = []CombineModes{0, 1, 2, 3}
+
+// CombineModesN is the highest valid value for type CombineModes, plus one.
+const CombineModesN CombineModes = 4
+
+var _CombineModesValueMap = map[string]CombineModes{`CombineAverage`: 0, `CombineMin`: 1, `CombineMax`: 2, `CombineMultiply`: 3}
+
+var _CombineModesDescMap = map[CombineModes]string{0: `CombineAverage uses the average of the two values`, 1: `CombineMin uses the smaller of the two values`, 2: `CombineMax uses the larger of the two values`, 3: `CombineMultiply uses the product of the two values`}
+
+var _CombineModesMap = map[CombineModes]string{0: `CombineAverage`, 1: `CombineMin`, 2: `CombineMax`, 3: `CombineMultiply`}
+
+// String returns the string representation of this CombineModes value.
+func (i CombineModes) String() string { return enums.String(i, _CombineModesMap) }
+
+// SetString sets the CombineModes value from its string representation,
+// and returns an error if the string is invalid.
+func (i *CombineModes) SetString(s string) error {
+	return enums.SetString(i, s, _CombineModesValueMap, "CombineModes")
+}
+
+// Int64 returns the CombineModes value as an int64.
+func (i CombineModes) Int64() int64 { return int64(i) }
+
+// SetInt64 sets the CombineModes value from an int64.
+func (i *CombineModes) SetInt64(in int64) { *i = CombineModes(in) }
+
+// Desc returns the description of the CombineModes value.
+func (i CombineModes) Desc() string { return enums.Desc(i, _CombineModesDescMap) }
+
+// CombineModesValues returns all possible values for the type CombineModes.
+func CombineModesValues() []CombineModes { return _CombineModesValues }
+
+// Values returns all possible values for the type CombineModes.
+func (i CombineModes) Values() []enums.Enum { return enums.Values(_CombineModesValues) }
+
+// MarshalText implements the [encoding.TextMarshaler] interface.
+func (i CombineModes) MarshalText() ([]byte, error) { return []byte(i.String()), nil }
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (i *CombineModes) UnmarshalText(text []byte) error {
+	return enums.UnmarshalText(i, text, "CombineModes")
+}
+
+var _NodeTypesValues = []NodeTypes{0, 1, 2, 3, 4}
 
 // NodeTypesN is the highest valid value for type NodeTypes, plus one.
-const NodeTypesN NodeTypes = 3
+const NodeTypesN NodeTypes = 5
 
-var _NodeTypesValueMap = map[string]NodeTypes{`BODY`: 0, `GROUP`: 1, `JOINT`: 2}
+var _NodeTypesValueMap = map[string]NodeTypes{`BODY`: 0, `GROUP`: 1, `JOINT`: 2, `SOFT`: 3, `PARTICLES`: 4}
 
-var _NodeTypesDescMap = map[NodeTypes]string{0: `note: uppercase required to not conflict with type names`, 1: ``, 2: ``}
+var _NodeTypesDescMap = map[NodeTypes]string{0: `note: uppercase required to not conflict with type names`, 1: ``, 2: ``, 3: ``, 4: ``}
 
-var _NodeTypesMap = map[NodeTypes]string{0: `BODY`, 1: `GROUP`, 2: `JOINT`}
+var _NodeTypesMap = map[NodeTypes]string{0: `BODY`, 1: `GROUP`, 2: `JOINT`, 3: `SOFT`, 4: `PARTICLES`}
 
 // String returns the string representation of this NodeTypes value.
 func (i NodeTypes) String() string { return enums.String(i, _NodeTypesMap) }
@@ -50,16 +136,16 @@ func (i *NodeTypes) UnmarshalText(text []byte) error {
 	return enums.UnmarshalText(i, text, "NodeTypes")
 }
 
-var _NodeFlagsValues = []NodeFlags{1}
+var _NodeFlagsValues = []NodeFlags{1, 2, 3, 4}
 
 // NodeFlagsN is the highest valid value for type NodeFlags, plus one.
-const NodeFlagsN NodeFlags = 2
+const NodeFlagsN NodeFlags = 5
 
-var _NodeFlagsValueMap = map[string]NodeFlags{`Dynamic`: 1}
+var _NodeFlagsValueMap = map[string]NodeFlags{`Dynamic`: 1, `Kinematic`: 2, `Proxy`: 3, `NoCollide`: 4}
 
-var _NodeFlagsDescMap = map[NodeFlags]string{1: `Dynamic means that this node can move -- if not so marked, it is a Static node. Any top-level group that is not Dynamic is immediately pruned from further consideration, so top-level groups should be separated into Dynamic and Static nodes at the start.`}
+var _NodeFlagsDescMap = map[NodeFlags]string{1: `Dynamic means that this node can move -- if not so marked, it is a Static node. Any top-level group that is not Dynamic is immediately pruned from further consideration in WorldCollide, so top-level groups should be separated into Dynamic and Static nodes at the start when using it. SpatialHash (WorldCollideAll) works for any layout.`, 2: `Kinematic means that this body is moved only by script, by setting Rel values and calling WorldRelToAbs, and not by physics. Its velocities are inferred from its motion, and it has infinite mass in the Solver, so that it pushes movable bodies out of its way. Kinematic bodies must also be Dynamic (see SetKinematic).`, 3: `Proxy means that the shape of this body is an invisible collision proxy, e.g., a simple Capsule for a detailed agent model: the views do not draw its shape, and instead show its Vis library entry as is, without sizing it to the shape (see SetProxy).`, 4: `NoCollide means that this body is a purely visual decoration, which is shown in the views but is skipped in all collision detection and collision queries (see SetNoCollide).`}
 
-var _NodeFlagsMap = map[NodeFlags]string{1: `Dynamic`}
+var _NodeFlagsMap = map[NodeFlags]string{1: `Dynamic`, 2: `Kinematic`, 3: `Proxy`, 4: `NoCollide`}
 
 // String returns the string representation of this NodeFlags value.
 func (i NodeFlags) String() string {
@@ -118,3 +204,89 @@ func (i NodeFlags) MarshalText() ([]byte, error) { return []byte(i.String()), ni
 func (i *NodeFlags) UnmarshalText(text []byte) error {
 	return enums.UnmarshalText(i, text, "NodeFlags")
 }
+
+var _PlanarModesValues = []PlanarModes{0, 1, 2, 3}
+
+// PlanarModesN is the highest valid value for type PlanarModes, plus one.
+const PlanarModesN PlanarModes = 4
+
+var _PlanarModesValueMap = map[string]PlanarModes{`NoPlanar`: 0, `PlanarXZ`: 1, `PlanarXY`: 2, `PlanarYZ`: 3}
+
+var _PlanarModesDescMap = map[PlanarModes]string{0: `NoPlanar does not constrain dynamics`, 1: `PlanarXZ constrains motion to the horizontal X-Z plane, with rotation only about the vertical Y axis, e.g., for top-down navigation as shown with the eve2d View ProjectXZ.`, 2: `PlanarXY constrains motion to the X-Y plane, with rotation only about the Z axis.`, 3: `PlanarYZ constrains motion to the Y-Z plane, with rotation only about the X axis.`}
+
+var _PlanarModesMap = map[PlanarModes]string{0: `NoPlanar`, 1: `PlanarXZ`, 2: `PlanarXY`, 3: `PlanarYZ`}
+
+// String returns the string representation of this PlanarModes value.
+func (i PlanarModes) String() string { return enums.String(i, _PlanarModesMap) }
+
+// SetString sets the PlanarModes value from its string representation,
+// and returns an error if the string is invalid.
+func (i *PlanarModes) SetString(s string) error {
+	return enums.SetString(i, s, _PlanarModesValueMap, "PlanarModes")
+}
+
+// Int64 returns the PlanarModes value as an int64.
+func (i PlanarModes) Int64() int64 { return int64(i) }
+
+// SetInt64 sets the PlanarModes value from an int64.
+func (i *PlanarModes) SetInt64(in int64) { *i = PlanarModes(in) }
+
+// Desc returns the description of the PlanarModes value.
+func (i PlanarModes) Desc() string { return enums.Desc(i, _PlanarModesDescMap) }
+
+// PlanarModesValues returns all possible values for the type PlanarModes.
+func PlanarModesValues() []PlanarModes { return _PlanarModesValues }
+
+// Values returns all possible values for the type PlanarModes.
+func (i PlanarModes) Values() []enums.Enum { return enums.Values(_PlanarModesValues) }
+
+// MarshalText implements the [encoding.TextMarshaler] interface.
+func (i PlanarModes) MarshalText() ([]byte, error) { return []byte(i.String()), nil }
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (i *PlanarModes) UnmarshalText(text []byte) error {
+	return enums.UnmarshalText(i, text, "PlanarModes")
+}
+
+var _DriveModesValues = []DriveModes{0, 1}
+
+// DriveModesN is the highest valid value for type DriveModes, plus one.
+const DriveModesN DriveModes = 2
+
+var _DriveModesValueMap = map[string]DriveModes{`DiffDrive`: 0, `Ackermann`: 1}
+
+var _DriveModesDescMap = map[DriveModes]string{0: `DiffDrive is differential drive: the Driven wheels on the left (negative X) and right (positive X) sides are driven at separate speeds, and the vehicle turns by driving them at different speeds (e.g., a two-wheeled robot with a Caster wheel).`, 1: `Ackermann is car-like steering: the Steer wheels are turned by the Steer angle, with the inner wheel turned more so that all the wheels roll around the same center, and the Driven wheels are driven at the Speed, scaled by their distance from that center.`}
+
+var _DriveModesMap = map[DriveModes]string{0: `DiffDrive`, 1: `Ackermann`}
+
+// String returns the string representation of this DriveModes value.
+func (i DriveModes) String() string { return enums.String(i, _DriveModesMap) }
+
+// SetString sets the DriveModes value from its string representation,
+// and returns an error if the string is invalid.
+func (i *DriveModes) SetString(s string) error {
+	return enums.SetString(i, s, _DriveModesValueMap, "DriveModes")
+}
+
+// Int64 returns the DriveModes value as an int64.
+func (i DriveModes) Int64() int64 { return int64(i) }
+
+// SetInt64 sets the DriveModes value from an int64.
+func (i *DriveModes) SetInt64(in int64) { *i = DriveModes(in) }
+
+// Desc returns the description of the DriveModes value.
+func (i DriveModes) Desc() string { return enums.Desc(i, _DriveModesDescMap) }
+
+// DriveModesValues returns all possible values for the type DriveModes.
+func DriveModesValues() []DriveModes { return _DriveModesValues }
+
+// Values returns all possible values for the type DriveModes.
+func (i DriveModes) Values() []enums.Enum { return enums.Values(_DriveModesValues) }
+
+// MarshalText implements the [encoding.TextMarshaler] interface.
+func (i DriveModes) MarshalText() ([]byte, error) { return []byte(i.String()), nil }
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (i *DriveModes) UnmarshalText(text []byte) error {
+	return enums.UnmarshalText(i, text, "DriveModes")
+}
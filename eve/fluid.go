@@ -0,0 +1,175 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package eve
+
+import (
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tree"
+)
+
+// Fluid is a region of fluid (e.g., the pool of a water maze), which
+// applies buoyancy and drag to the movable bodies that are submerged in it,
+// when added to Solver.Fluids.  The region is an axis-aligned box in world
+// coords, with the surface of the fluid at the top (Region.Max.Y).
+// The fluid is not visible: add a NoCollide body to show it in the views.
+type Fluid struct {
+
+	// name of the fluid, for reference
+	Name string
+
+	// region occupied by the fluid, in world coords, with the surface at Region.Max.Y
+	Region math32.Box3
+
+	// density of the fluid, in units of mass per volume, which determines the buoyancy: bodies float if their average density is less than this -- water is 1000 kg/m^3
+	Density float32 `default:"1000"`
+
+	// linear drag coefficient, as force per unit velocity relative to the fluid, for a fully submerged body
+	LinDrag float32
+
+	// quadratic drag coefficient, as force per unit squared velocity relative to the fluid, for a fully submerged body
+	QuadDrag float32
+
+	// angular drag rate, as the proportion of angular velocity lost per unit time, for a fully submerged body
+	AngDrag float32
+
+	// velocity of the fluid (e.g., a current), which drag pulls bodies toward
+	Flow math32.Vector3
+}
+
+func (fl *Fluid) Defaults() {
+	fl.Density = 1000
+}
+
+// Surface returns the height of the surface of the fluid
+func (fl *Fluid) Surface() float32 {
+	return fl.Region.Max.Y
+}
+
+// Contains returns true if given point in world coords is in the fluid
+func (fl *Fluid) Contains(pt math32.Vector3) bool {
+	return fl.Region.ContainsPoint(pt)
+}
+
+// Depth returns the depth of given point in world coords below the surface,
+// which is negative for points above the surface
+func (fl *Fluid) Depth(pt math32.Vector3) float32 {
+	return fl.Region.Max.Y - pt.Y
+}
+
+// Submerged returns the proportion of the volume of given body that is
+// submerged in the fluid, based on its current Abs pose.  This is exact for
+// boxes that are aligned with the vertical, and for uniformly scaled
+// spheres, and is otherwise based on the proportion of the height of the
+// body that is submerged.  Bodies whose center is not within the horizontal
+// extent of the Region are not submerged.
+func (fl *Fluid) Submerged(bod Body) float32 {
+	nb := bod.AsNodeBase()
+	pos := nb.Abs.Pos
+	if pos.X < fl.Region.Min.X || pos.X > fl.Region.Max.X || pos.Z < fl.Region.Min.Z || pos.Z > fl.Region.Max.Z {
+		return 0
+	}
+	sp := bodyPose(bod)
+	bot := sp.support(math32.Vec3(0, -1, 0)).Y
+	top := sp.support(math32.Vec3(0, 1, 0)).Y
+	if top <= bot {
+		return 0
+	}
+	lo := max(bot, fl.Region.Min.Y)
+	hi := min(top, fl.Region.Max.Y)
+	if hi <= lo {
+		return 0
+	}
+	if _, ok := bod.(*Sphere); ok && isUniform(sp.scale) && lo == bot {
+		t := (hi - bot) / (top - bot)
+		return t * t * (3 - 2*t) // spherical cap
+	}
+	return (hi - lo) / (top - bot)
+}
+
+// Apply applies buoyancy and drag to the velocity of given movable body
+// for given time step and gravity, subject to given lock factors for
+// linear and angular motion.  Returns the proportion of the body that
+// is submerged.
+func (fl *Fluid) Apply(bod Body, step float32, gravity, lin, ang math32.Vector3) float32 {
+	sub := fl.Submerged(bod)
+	if sub == 0 {
+		return 0
+	}
+	bb := bod.AsBodyBase()
+	im := bb.ScaledInvMass()
+	buoy := gravity.MulScalar(-fl.Density * BodyVolume(bod) * sub * im * step)
+	bb.Abs.LinVel.SetAdd(buoy.Mul(lin))
+	rv := bb.Abs.LinVel.Sub(fl.Flow)
+	if spd := rv.Length(); spd > 0 {
+		dspd := min((fl.LinDrag+fl.QuadDrag*spd)*sub*im*step, 1) * spd // no reversal
+		bb.Abs.LinVel.SetSub(rv.MulScalar(dspd / spd).Mul(lin))
+	}
+	if fl.AngDrag > 0 {
+		bb.Abs.AngVel.SetSub(bb.Abs.AngVel.MulScalar(min(fl.AngDrag*sub*step, 1)).Mul(ang))
+	}
+	return sub
+}
+
+// BodyVolume returns the volume of the collision shape of given body,
+// at its current Abs.Scale, using the BBox volume for shapes without
+// a specific volume (e.g., Hull).
+func BodyVolume(bod Body) float32 {
+	sc := bod.AsNodeBase().Abs.ScaleFactor()
+	svol := sc.X * sc.Y * sc.Z
+	switch sh := bod.(type) {
+	case *Box:
+		return sh.Size.X * sh.Size.Y * sh.Size.Z * svol
+	case *Sphere:
+		return 4 * math32.Pi / 3 * sh.Radius * sh.Radius * sh.Radius * svol
+	case *Cylinder:
+		return frustumVolume(sh.Height, sh.TopRad, sh.BotRad) * svol
+	case *Capsule:
+		hemi := 2 * math32.Pi / 3 * (sh.TopRad*sh.TopRad*sh.TopRad + sh.BotRad*sh.BotRad*sh.BotRad)
+		return (frustumVolume(sh.Height, sh.TopRad, sh.BotRad) + hemi) * svol
+	}
+	return bod.AsNodeBase().BBox.Volume
+}
+
+// frustumVolume returns the volume of a truncated cone of given height
+// and top and bottom radii
+func frustumVolume(h, r1, r2 float32) float32 {
+	return math32.Pi * h / 3 * (r1*r1 + r1*r2 + r2*r2)
+}
+
+// FluidAt returns the first of the Fluids that contains given point in
+// world coords, or nil if none, e.g., to determine if a scripted agent
+// is swimming.
+func (sv *Solver) FluidAt(pt math32.Vector3) *Fluid {
+	for _, fl := range sv.Fluids {
+		if fl.Contains(pt) {
+			return fl
+		}
+	}
+	return nil
+}
+
+// ApplyFluids applies buoyancy and drag from all the Fluids to the
+// velocities of the movable bodies in the world, subject to their LockFactors.
+func (sv *Solver) ApplyFluids(world *Group, step float32) {
+	if len(sv.Fluids) == 0 {
+		return
+	}
+	world.WalkDown(func(k tree.Node) bool {
+		nii, _ := AsNode(k)
+		if nii == nil {
+			return false // going into a different type of thing, bail
+		}
+		if !nii.IsDynamic() {
+			return false
+		}
+		if bod := nii.AsBody(); bod != nil && IsMovable(bod) {
+			lin, ang := sv.LockFactors(bod.AsBodyBase())
+			for _, fl := range sv.Fluids {
+				fl.Apply(bod, step, sv.Gravity, lin, ang)
+			}
+		}
+		return true
+	})
+}
@@ -0,0 +1,537 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package eve
+
+import (
+	"cogentcore.org/core/math32"
+)
+
+// This file implements the narrow phase of collision detection for
+// convex shapes, using the GJK (Gilbert-Johnson-Keerthi) algorithm
+// to compute the distance and closest points between separated shapes,
+// and EPA (Expanding Polytope Algorithm) to compute the penetration
+// depth and contact points for overlapping shapes.  Both operate
+// entirely on the Support function of each Body.
+
+const (
+	// gjkMaxIters is the maximum number of GJK iterations
+	gjkMaxIters = 64
+
+	// epaMaxIters is the maximum number of EPA iterations
+	epaMaxIters = 64
+
+	// gjkRelTol is the relative tolerance for GJK convergence
+	gjkRelTol = 1.0e-6
+
+	// epaTol is the tolerance for EPA convergence
+	epaTol = 1.0e-4
+)
+
+// BodySupport returns the point on the collision shape of given body
+// that is farthest along given direction, in world coordinates.
+func BodySupport(bod Body, dir math32.Vector3) math32.Vector3 {
+	sp := bodyPose(bod)
+	return sp.support(dir)
+}
+
+// shapePose is the collision shape of a body at a given position and
+// orientation in world coordinates, which may differ from its current
+// Abs pose, e.g., for perturbed or swept queries.
+type shapePose struct {
+	bod   Body
+	pos   math32.Vector3
+	quat  math32.Quat
+	scale math32.Vector3
+}
+
+// bodyPose returns the shapePose for the current Abs pose of the body
+func bodyPose(bod Body) shapePose {
+	nb := bod.AsNodeBase()
+	return shapePose{bod: bod, pos: nb.Abs.Pos, quat: nb.Abs.Quat, scale: nb.Abs.ScaleFactor()}
+}
+
+// support returns the point on the shape farthest along dir, in world coordinates.
+// The support point of a shape scaled by S along its local axes is
+// S times the support point of the unscaled shape along S times dir.
+func (sp *shapePose) support(dir math32.Vector3) math32.Vector3 {
+	iq := sp.quat.Conjugate()
+	ldir := dir.MulQuat(iq).Mul(sp.scale)
+	return sp.bod.Support(ldir).Mul(sp.scale).MulQuat(sp.quat).Add(sp.pos)
+}
+
+// isUniform returns true if given scale is the same along all axes
+func isUniform(sc math32.Vector3) bool {
+	return sc.X == sc.Y && sc.Y == sc.Z
+}
+
+// mkPt is a point in the Minkowski difference of two shapes A - B,
+// along with the support points on A and B that generated it.
+type mkPt struct {
+	A, B, W math32.Vector3
+}
+
+// mkSupport returns the Minkowski support point of A - B along dir,
+// for the core shapes of A and B shrunk by given margins (see shapeMargin).
+func mkSupport(a, b *shapePose, dir math32.Vector3, ma, mb float32) mkPt {
+	pa := a.support(dir)
+	pb := b.support(dir.Negate())
+	if ma > 0 || mb > 0 {
+		nd := dir.Normal()
+		pa.SetSub(nd.MulScalar(ma))
+		pb.SetAdd(nd.MulScalar(mb))
+	}
+	return mkPt{A: pa, B: pb, W: pa.Sub(pb)}
+}
+
+// shapeMargin returns the radius of the sphere that sweeps a core shape
+// to generate the body shape, for round shapes: a Sphere is a point
+// swept by its Radius, and a Capsule with equal radii is a line segment.
+// Computing distances between the core shapes and then subtracting the
+// margins is both faster and more accurate than working with the
+// round shapes directly.  Non-uniformly scaled shapes are not round.
+func shapeMargin(sp *shapePose) float32 {
+	if !isUniform(sp.scale) {
+		return 0
+	}
+	switch sh := sp.bod.(type) {
+	case *Sphere:
+		return sh.Radius * sp.scale.X
+	case *Capsule:
+		if sh.TopRad == sh.BotRad {
+			return sh.TopRad * sp.scale.X
+		}
+	}
+	return 0
+}
+
+// ShapeDist returns the signed distance between the collision shapes
+// of the two bodies: positive when separated and negative when overlapping,
+// by the penetration depth.  Also returns the closest (or deepest) points
+// on A and B in world coordinates, and the normal pointing from B toward A,
+// which is the direction to move A to separate it from B.
+func ShapeDist(a, b Body) (dist float32, ptA, ptB, norm math32.Vector3) {
+	pa := bodyPose(a)
+	pb := bodyPose(b)
+	return shapeDist(&pa, &pb)
+}
+
+// shapeDist is ShapeDist for shapes at given poses
+func shapeDist(a, b *shapePose) (dist float32, ptA, ptB, norm math32.Vector3) {
+	ma := shapeMargin(a)
+	mb := shapeMargin(b)
+	_, sa := a.bod.(*Sphere)
+	_, sb := b.bod.(*Sphere)
+	if sa && sb && isUniform(a.scale) && isUniform(b.scale) {
+		return sphereDist(a.pos, b.pos, ma, mb)
+	}
+	var smp [4]mkPt
+	if ma > 0 || mb > 0 {
+		n, v, inter := gjk(a, b, &smp, ma, mb)
+		if !inter && v.LengthSquared() > 1.0e-10 {
+			dist, ptA, ptB, norm = gjkResult(smp[:n], v)
+			ptA.SetSub(norm.MulScalar(ma))
+			ptB.SetAdd(norm.MulScalar(mb))
+			return dist - ma - mb, ptA, ptB, norm
+		}
+	}
+	n, v, inter := gjk(a, b, &smp, 0, 0)
+	if !inter && v.LengthSquared() > 1.0e-10 { // otherwise too close to tell, e.g., for symmetric overlaps
+		return gjkResult(smp[:n], v)
+	}
+	depth, pa, pb, nrm, ok := epa(a, b, smp[:n])
+	if !ok {
+		// degenerate: fall back on centers
+		norm = a.pos.Sub(b.pos)
+		if norm.LengthSquared() < 1.0e-12 {
+			norm = math32.Vec3(0, 1, 0)
+		}
+		norm.SetNormal()
+		return 0, a.pos, a.pos, norm
+	}
+	return -depth, pa, pb, nrm
+}
+
+// gjkResult returns the distance, closest points and normal
+// from the final simplex and closest point v of a GJK run
+// on separated shapes.
+func gjkResult(smp []mkPt, v math32.Vector3) (dist float32, ptA, ptB, norm math32.Vector3) {
+	lam := simplexBary(smp)
+	for i := range smp {
+		ptA.SetAdd(smp[i].A.MulScalar(lam[i]))
+		ptB.SetAdd(smp[i].B.MulScalar(lam[i]))
+	}
+	dist = v.Length()
+	norm = v.DivScalar(dist)
+	return
+}
+
+// sphereDist is the exact distance between two spheres at given positions,
+// with given radii
+func sphereDist(posA, posB math32.Vector3, radA, radB float32) (dist float32, ptA, ptB, norm math32.Vector3) {
+	d := posA.Sub(posB)
+	l := d.Length()
+	if l < 1.0e-6 {
+		norm = math32.Vec3(0, 1, 0)
+	} else {
+		norm = d.DivScalar(l)
+	}
+	dist = l - radA - radB
+	ptA = posA.Sub(norm.MulScalar(radA))
+	ptB = posB.Add(norm.MulScalar(radB))
+	return
+}
+
+// gjk runs the GJK distance algorithm on the two shapes, with shapes
+// shrunk by given margins, returning the final simplex in smp with n points,
+// the closest point v in the Minkowski difference to the origin,
+// and whether the shapes intersect.
+func gjk(a, b *shapePose, smp *[4]mkPt, ma, mb float32) (n int, v math32.Vector3, inter bool) {
+	v = a.pos.Sub(b.pos)
+	if v.LengthSquared() < 1.0e-12 {
+		v = math32.Vec3(1, 0, 0)
+	}
+	smp[0] = mkSupport(a, b, v.Negate(), ma, mb)
+	v = smp[0].W
+	n = 1
+	for iter := 0; iter < gjkMaxIters; iter++ {
+		vv := v.LengthSquared()
+		if vv < 1.0e-12 {
+			return n, v, true
+		}
+		w := mkSupport(a, b, v.Negate(), ma, mb)
+		if vv-v.Dot(w.W) <= gjkRelTol*vv {
+			return n, v, false
+		}
+		dup := false
+		for i := 0; i < n; i++ {
+			if smp[i].W.DistanceToSquared(w.W) < 1.0e-12 {
+				dup = true
+				break
+			}
+		}
+		if dup {
+			return n, v, false
+		}
+		smp[n] = w
+		n++
+		var cv math32.Vector3
+		n, cv = simplexClosest(smp, n)
+		if n == 4 {
+			return n, v, true
+		}
+		if cv.LengthSquared() >= vv {
+			return n, cv, false // no progress
+		}
+		v = cv
+	}
+	return n, v, false
+}
+
+// simplexClosest computes the point closest to the origin in the
+// simplex of n points, and reduces the simplex to the smallest
+// sub-simplex that contains that point.  If the origin is contained
+// in a tetrahedron, n remains 4.
+func simplexClosest(smp *[4]mkPt, n int) (int, math32.Vector3) {
+	switch n {
+	case 1:
+		return 1, smp[0].W
+	case 2:
+		return closestSegment(smp)
+	case 3:
+		return closestTriangle(smp)
+	}
+	return closestTetra(smp)
+}
+
+// closestSegment reduces a 2-point simplex
+func closestSegment(smp *[4]mkPt) (int, math32.Vector3) {
+	a := smp[0].W
+	ab := smp[1].W.Sub(a)
+	t := -a.Dot(ab)
+	if t <= 0 {
+		return 1, a
+	}
+	den := ab.LengthSquared()
+	if t >= den {
+		smp[0] = smp[1]
+		return 1, smp[0].W
+	}
+	return 2, a.Add(ab.MulScalar(t / den))
+}
+
+// closestTriangle reduces a 3-point simplex, following the
+// Voronoi-region tests from Ericson, Real-Time Collision Detection.
+func closestTriangle(smp *[4]mkPt) (int, math32.Vector3) {
+	a, b, c := smp[0].W, smp[1].W, smp[2].W
+	ab := b.Sub(a)
+	ac := c.Sub(a)
+	ap := a.Negate()
+	d1 := ab.Dot(ap)
+	d2 := ac.Dot(ap)
+	if d1 <= 0 && d2 <= 0 {
+		return 1, a
+	}
+	bp := b.Negate()
+	d3 := ab.Dot(bp)
+	d4 := ac.Dot(bp)
+	if d3 >= 0 && d4 <= d3 {
+		smp[0] = smp[1]
+		return 1, b
+	}
+	vc := d1*d4 - d3*d2
+	if vc <= 0 && d1 >= 0 && d3 <= 0 {
+		t := d1 / (d1 - d3)
+		return 2, a.Add(ab.MulScalar(t))
+	}
+	cp := c.Negate()
+	d5 := ab.Dot(cp)
+	d6 := ac.Dot(cp)
+	if d6 >= 0 && d5 <= d6 {
+		smp[0] = smp[2]
+		return 1, c
+	}
+	vb := d5*d2 - d1*d6
+	if vb <= 0 && d2 >= 0 && d6 <= 0 {
+		t := d2 / (d2 - d6)
+		smp[1] = smp[2]
+		return 2, a.Add(ac.MulScalar(t))
+	}
+	va := d3*d6 - d5*d4
+	if va <= 0 && (d4-d3) >= 0 && (d5-d6) >= 0 {
+		t := (d4 - d3) / ((d4 - d3) + (d5 - d6))
+		smp[0] = smp[2]
+		return 2, b.Add(c.Sub(b).MulScalar(t))
+	}
+	den := 1 / (va + vb + vc)
+	v := vb * den
+	w := vc * den
+	return 3, a.Add(ab.MulScalar(v)).Add(ac.MulScalar(w))
+}
+
+// closestTetra reduces a 4-point simplex, returning 4 if the
+// origin is contained within it.  A flat tetrahedron, which arises
+// when the new point is in the plane of the triangle (e.g., on a face
+// of a box), cannot contain the origin, so all of its faces are tested.
+func closestTetra(smp *[4]mkPt) (int, math32.Vector3) {
+	faces := [4][3]int{{0, 1, 2}, {0, 2, 3}, {0, 3, 1}, {1, 3, 2}}
+	opp := [4]int{3, 1, 2, 0}
+	ab := smp[1].W.Sub(smp[0].W)
+	ac := smp[2].W.Sub(smp[0].W)
+	ad := smp[3].W.Sub(smp[0].W)
+	vol := ab.Cross(ac).Dot(ad)
+	flat := math32.Abs(vol) <= 1.0e-5*ab.Length()*ac.Length()*ad.Length()
+	best := float32(math32.Infinity)
+	bn := 4
+	var bv math32.Vector3
+	var bs [4]mkPt
+	for fi, f := range faces {
+		a, b, c := smp[f[0]].W, smp[f[1]].W, smp[f[2]].W
+		nrm := b.Sub(a).Cross(c.Sub(a))
+		sa := a.Dot(nrm)                     // origin side: -sa
+		sd := smp[opp[fi]].W.Sub(a).Dot(nrm) // opposite vertex side
+		if !flat && (sa*sd < 0 || sd == 0) {
+			continue // origin on same side as opposite vertex
+		}
+		var fs [4]mkPt
+		fs[0], fs[1], fs[2] = smp[f[0]], smp[f[1]], smp[f[2]]
+		fn, fv := closestTriangle(&fs)
+		if d := fv.LengthSquared(); d < best {
+			best = d
+			bn = fn
+			bv = fv
+			bs = fs
+		}
+	}
+	if bn == 4 {
+		return 4, math32.Vector3{}
+	}
+	*smp = bs
+	return bn, bv
+}
+
+// simplexBary returns the barycentric coordinates of the point
+// closest to the origin in the given (reduced) simplex.
+func simplexBary(smp []mkPt) [4]float32 {
+	var lam [4]float32
+	switch len(smp) {
+	case 1:
+		lam[0] = 1
+	case 2:
+		a := smp[0].W
+		ab := smp[1].W.Sub(a)
+		den := ab.LengthSquared()
+		t := float32(0)
+		if den > 0 {
+			t = math32.Clamp(-a.Dot(ab)/den, 0, 1)
+		}
+		lam[0] = 1 - t
+		lam[1] = t
+	case 3:
+		u, v, w := triBary(smp[0].W, smp[1].W, smp[2].W, math32.Vector3{})
+		lam[0], lam[1], lam[2] = u, v, w
+	}
+	return lam
+}
+
+// triBary returns the barycentric coordinates of the projection of
+// point p onto the plane of triangle a, b, c.
+func triBary(a, b, c, p math32.Vector3) (u, v, w float32) {
+	v0 := b.Sub(a)
+	v1 := c.Sub(a)
+	v2 := p.Sub(a)
+	d00 := v0.Dot(v0)
+	d01 := v0.Dot(v1)
+	d11 := v1.Dot(v1)
+	d20 := v2.Dot(v0)
+	d21 := v2.Dot(v1)
+	den := d00*d11 - d01*d01
+	if math32.Abs(den) < 1.0e-12 {
+		return 1, 0, 0
+	}
+	v = (d11*d20 - d01*d21) / den
+	w = (d00*d21 - d01*d20) / den
+	u = 1 - v - w
+	return
+}
+
+// epaFace is a triangular face of the expanding polytope
+type epaFace struct {
+	I    [3]int
+	Norm math32.Vector3
+	Dist float32
+}
+
+// epa runs the expanding polytope algorithm starting from a GJK simplex
+// that contains the origin, returning the penetration depth,
+// the deepest points on A and B, and the normal pointing from B toward A.
+func epa(a, b *shapePose, smp []mkPt) (depth float32, ptA, ptB, norm math32.Vector3, ok bool) {
+	pts := make([]mkPt, 0, 32)
+	pts = append(pts, smp...)
+	pts = epaTetra(a, b, pts)
+	if len(pts) < 4 {
+		return
+	}
+	// interior point used to orient faces, as the origin may be on the surface
+	ctr := pts[0].W.Add(pts[1].W).Add(pts[2].W).Add(pts[3].W).MulScalar(0.25)
+	faces := make([]epaFace, 0, 64)
+	addFace := func(i, j, k int) {
+		pa, pb, pc := pts[i].W, pts[j].W, pts[k].W
+		nrm := pb.Sub(pa).Cross(pc.Sub(pa))
+		l := nrm.Length()
+		if l < 1.0e-12 {
+			return
+		}
+		nrm = nrm.DivScalar(l)
+		if nrm.Dot(pa.Sub(ctr)) < 0 {
+			nrm = nrm.Negate()
+			j, k = k, j
+		}
+		faces = append(faces, epaFace{I: [3]int{i, j, k}, Norm: nrm, Dist: nrm.Dot(pa)})
+	}
+	addFace(0, 1, 2)
+	addFace(0, 3, 1)
+	addFace(0, 2, 3)
+	addFace(1, 3, 2)
+	if len(faces) < 4 {
+		return
+	}
+	var best epaFace
+	for iter := 0; iter < epaMaxIters; iter++ {
+		bi := 0
+		for fi := range faces {
+			if faces[fi].Dist < faces[bi].Dist {
+				bi = fi
+			}
+		}
+		best = faces[bi]
+		w := mkSupport(a, b, best.Norm, 0, 0)
+		wd := best.Norm.Dot(w.W)
+		if wd-best.Dist < epaTol {
+			break
+		}
+		wi := len(pts)
+		pts = append(pts, w)
+		type edge struct{ a, b int }
+		var edges []edge
+		nf := faces[:0]
+		for _, f := range faces {
+			if f.Norm.Dot(w.W.Sub(pts[f.I[0]].W)) > 0 {
+				for e := 0; e < 3; e++ {
+					ed := edge{f.I[e], f.I[(e+1)%3]}
+					found := false
+					for ei, oe := range edges {
+						if oe.a == ed.b && oe.b == ed.a {
+							edges = append(edges[:ei], edges[ei+1:]...)
+							found = true
+							break
+						}
+					}
+					if !found {
+						edges = append(edges, ed)
+					}
+				}
+				continue
+			}
+			nf = append(nf, f)
+		}
+		faces = nf
+		for _, ed := range edges {
+			addFace(ed.a, ed.b, wi)
+		}
+		if len(faces) == 0 {
+			return
+		}
+	}
+	u, v, w := triBary(pts[best.I[0]].W, pts[best.I[1]].W, pts[best.I[2]].W, best.Norm.MulScalar(best.Dist))
+	p0, p1, p2 := pts[best.I[0]], pts[best.I[1]], pts[best.I[2]]
+	ptA = p0.A.MulScalar(u).Add(p1.A.MulScalar(v)).Add(p2.A.MulScalar(w))
+	ptB = p0.B.MulScalar(u).Add(p1.B.MulScalar(v)).Add(p2.B.MulScalar(w))
+	return best.Dist, ptA, ptB, best.Norm.Negate(), true
+}
+
+// epaTetra expands a GJK simplex of fewer than 4 points, which
+// arises when the shapes are just touching, into a tetrahedron.
+func epaTetra(a, b *shapePose, pts []mkPt) []mkPt {
+	axes := [6]math32.Vector3{math32.Vec3(1, 0, 0), math32.Vec3(-1, 0, 0), math32.Vec3(0, 1, 0), math32.Vec3(0, -1, 0), math32.Vec3(0, 0, 1), math32.Vec3(0, 0, -1)}
+	if len(pts) == 1 {
+		for _, ax := range axes {
+			w := mkSupport(a, b, ax, 0, 0)
+			if w.W.DistanceToSquared(pts[0].W) > 1.0e-10 {
+				pts = append(pts, w)
+				break
+			}
+		}
+	}
+	if len(pts) == 2 {
+		d := pts[1].W.Sub(pts[0].W)
+		for ai := 0; ai < 6; ai += 2 {
+			perp := d.Cross(axes[ai])
+			if perp.LengthSquared() < 1.0e-10 {
+				continue
+			}
+			w := mkSupport(a, b, perp, 0, 0)
+			if w.W.Sub(pts[0].W).Cross(d).LengthSquared() > 1.0e-10 {
+				pts = append(pts, w)
+				break
+			}
+			w = mkSupport(a, b, perp.Negate(), 0, 0)
+			if w.W.Sub(pts[0].W).Cross(d).LengthSquared() > 1.0e-10 {
+				pts = append(pts, w)
+				break
+			}
+		}
+	}
+	if len(pts) == 3 {
+		nrm := pts[1].W.Sub(pts[0].W).Cross(pts[2].W.Sub(pts[0].W))
+		w := mkSupport(a, b, nrm, 0, 0)
+		if math32.Abs(w.W.Sub(pts[0].W).Dot(nrm)) < 1.0e-10 {
+			w = mkSupport(a, b, nrm.Negate(), 0, 0)
+		}
+		if math32.Abs(w.W.Sub(pts[0].W).Dot(nrm)) > 1.0e-10 {
+			pts = append(pts, w)
+		}
+	}
+	return pts
+}
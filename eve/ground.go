@@ -0,0 +1,174 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package eve
+
+import (
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tree"
+)
+
+// groundSkin is the distance above the ground at which a downward cast stops
+const groundSkin = 1.0e-3
+
+// probePoint is the shape used for casting a point: a sphere of zero radius
+var probePoint = &Sphere{}
+
+// GroundHit is the supporting surface found below a body or point
+type GroundHit struct {
+
+	// the static body that was hit
+	Body Body
+
+	// point of contact on the surface of the body, in world coords
+	Point math32.Vector3
+
+	// height of the surface at the point of contact (Point.Y)
+	Height float32
+
+	// normal of the surface at the point of contact, pointing up
+	Normal math32.Vector3
+
+	// angle of the surface from horizontal, in degrees
+	Slope float32
+
+	// distance that the body or point moved down before hitting the surface
+	Dist float32
+}
+
+// GroundUnder returns the supporting surface under given body, by sweeping
+// its collision shape straight down (along -Y) from its current Abs pose,
+// up to given maximum distance, against the static (non-Dynamic) bodies
+// in the world.  Returns false if there is no surface within range.
+func (gp *Group) GroundUnder(bod Body, maxDist float32) (GroundHit, bool) {
+	return gp.groundCast(bodyPose(bod), maxDist, bod)
+}
+
+// GroundAt returns the supporting surface under given point in world coords,
+// by casting a ray straight down (along -Y), up to given maximum distance,
+// against the static (non-Dynamic) bodies in the world.
+// Returns false if there is no surface within range.
+func (gp *Group) GroundAt(pt math32.Vector3, maxDist float32) (GroundHit, bool) {
+	return gp.groundCast(pointPose(pt), maxDist, nil)
+}
+
+// groundCast casts the shape at given pose down by given distance against
+// the static bodies in the world, skipping the subtree at skip if non-nil.
+func (gp *Group) groundCast(sp shapePose, maxDist float32, skip tree.Node) (GroundHit, bool) {
+	var gh GroundHit
+	box := shapeBox(&sp)
+	box.Min.Y -= maxDist
+	motion := math32.Vec3(0, -maxDist, 0)
+	t := float32(1)
+	for _, ob := range gp.BodiesInBox(box, skip) {
+		if ob.AsNodeBase().IsDynamic() {
+			continue
+		}
+		op := bodyPose(ob)
+		if ht, _, ok := shapeCast(&sp, motion, &op, groundSkin); ok && ht <= t {
+			t = ht
+			gh.Body = ob
+		}
+	}
+	if gh.Body == nil {
+		return gh, false
+	}
+	sp.pos.SetAdd(motion.MulScalar(t))
+	op := bodyPose(gh.Body)
+	_, _, gh.Point, gh.Normal = shapeDist(&sp, &op)
+	gh.Height = gh.Point.Y
+	gh.Slope = math32.RadToDeg(math32.Acos(math32.Clamp(gh.Normal.Y, -1, 1)))
+	gh.Dist = t * maxDist
+	return gh, true
+}
+
+// pointPose returns the shapePose for a point at given position
+func pointPose(pt math32.Vector3) shapePose {
+	return shapePose{bod: probePoint, pos: pt, quat: math32.NewQuat(0, 0, 0, 1), scale: math32.Vec3(1, 1, 1)}
+}
+
+// shapeBox returns the axis-aligned bounding box of the shape at given pose
+func shapeBox(sp *shapePose) math32.Box3 {
+	return math32.Box3{
+		Min: math32.Vec3(sp.support(math32.Vec3(-1, 0, 0)).X, sp.support(math32.Vec3(0, -1, 0)).Y, sp.support(math32.Vec3(0, 0, -1)).Z),
+		Max: math32.Vec3(sp.support(math32.Vec3(1, 0, 0)).X, sp.support(math32.Vec3(0, 1, 0)).Y, sp.support(math32.Vec3(0, 0, 1)).Z),
+	}
+}
+
+//////////////////////////////////////////////////////////////////////
+//  GroundSnap
+
+// GroundSnap keeps a scripted object (e.g., an agent) on the ground, by
+// moving its Root node up or down onto the supporting surface found under
+// its Body (see GroundUnder), or under the Root position if Body is nil,
+// each time Snap is called.  It can be added to Solver.Snaps so that Snap
+// is called at the start of each Solver.Step, after the Rel values have been
+// updated by script.  Surfaces steeper than MaxSlope are not snapped onto,
+// and nothing is done if there is no surface within MaxDrop below,
+// e.g., when walking off a cliff.
+type GroundSnap struct {
+
+	// the node that is moved: the Body or a Group containing it
+	Root Node
+
+	// the body whose collision shape is placed on the ground -- if nil, the Root position is placed on the ground
+	Body Body
+
+	// maximum distance that the object is moved up onto a higher surface (e.g., up a ramp)
+	MaxRise float32 `default:"0.3"`
+
+	// maximum distance that the object is moved down onto a lower surface
+	MaxDrop float32 `default:"0.5"`
+
+	// maximum slope in degrees of surfaces that are snapped onto
+	MaxSlope float32 `default:"45"`
+
+	// the surface that the object was last snapped onto
+	Ground GroundHit `edit:"-"`
+
+	// true if the object was snapped onto the ground on the last call to Snap
+	Snapped bool `edit:"-"`
+}
+
+// NewGroundSnap returns a new GroundSnap for given root node and body
+// (which can be nil), with default parameters
+func NewGroundSnap(root Node, body Body) *GroundSnap {
+	gs := &GroundSnap{Root: root, Body: body}
+	gs.Defaults()
+	return gs
+}
+
+func (gs *GroundSnap) Defaults() {
+	gs.MaxRise = 0.3
+	gs.MaxDrop = 0.5
+	gs.MaxSlope = 45
+}
+
+// Snap moves the Root up or down onto the supporting surface in given world,
+// by updating its Rel.Pos in the coordinates of its parent.  The pose of the
+// object is computed from the current Rel values, so Snap can be called
+// after scripted updates and before WorldRelToAbs, which must then be called
+// to update the Abs values (as is done in Solver.Step).
+// Returns true if the object was snapped.
+func (gs *GroundSnap) Snap(world *Group) bool {
+	gs.Snapped = false
+	rb := gs.Root.AsNodeBase()
+	var sp shapePose
+	if gs.Body != nil {
+		ps := gs.Body.AsNodeBase().relWorld()
+		sp = shapePose{bod: gs.Body, pos: ps.Pos, quat: ps.Quat, scale: ps.Scale}
+	} else {
+		ps := rb.relWorld()
+		sp = pointPose(ps.Pos)
+	}
+	sp.pos.Y += gs.MaxRise
+	gh, ok := world.groundCast(sp, gs.MaxRise+gs.MaxDrop, gs.Root)
+	if !ok || gh.Slope > gs.MaxSlope {
+		return false
+	}
+	gs.Ground = gh
+	gs.Snapped = true
+	rb.addRelPos(math32.Vec3(0, gs.MaxRise-gh.Dist, 0))
+	return true
+}
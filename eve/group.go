@@ -17,6 +17,9 @@ import (
 // Use a group for the top-level World node as well.
 type Group struct {
 	NodeBase
+
+	// static geometry that is shared with other worlds (e.g., the worlds of a Batch), which is not part of the tree of this top-level world group, but is included in its collisions and queries as if it were
+	Shared *Group `copier:"-" json:"-" xml:"-" display:"-"`
 }
 
 func (gp *Group) EveNodeType() NodeTypes {
@@ -55,7 +58,14 @@ func (gp *Group) GroupBBox() {
 
 // WorldDynGroupBBox does a GroupBBox on all dynamic nodes
 func (gp *Group) WorldDynGroupBBox() {
-	gp.WalkDownPost(func(k tree.Node) bool {
+	gp.WorldDynGroupBBoxParallel(1)
+}
+
+// WorldDynGroupBBoxParallel does WorldDynGroupBBox, with the subtrees of
+// each of the children of this group updated in parallel, using up to
+// the given number of goroutines.
+func (gp *Group) WorldDynGroupBBoxParallel(workers int) {
+	gp.walkDownPostParallel(workers, func(k tree.Node) bool {
 		nii, _ := AsNode(k)
 		if nii == nil {
 			return false
@@ -109,7 +119,14 @@ func (gp *Group) WorldInit() {
 // WorldRelToAbs does a full RelToAbs update for all Dynamic groups, for
 // Scripted mode updates with manual updating of Rel values.
 func (gp *Group) WorldRelToAbs() {
-	gp.WalkDown(func(k tree.Node) bool {
+	gp.WorldRelToAbsParallel(1)
+}
+
+// WorldRelToAbsParallel does WorldRelToAbs, with the independent subtrees
+// of each of the children of this group updated in parallel, using up
+// to the given number of goroutines, with results identical to WorldRelToAbs.
+func (gp *Group) WorldRelToAbsParallel(workers int) {
+	gp.walkDownParallel(workers, func(k tree.Node) bool {
 		nii, _ := AsNode(k)
 		if nii == nil {
 			return false // going into a different type of thing, bail
@@ -122,25 +139,63 @@ func (gp *Group) WorldRelToAbs() {
 		return true
 	})
 
-	gp.WorldDynGroupBBox()
+	gp.WorldDynGroupBBoxParallel(workers)
 }
 
 // WorldStepPhys does a full StepPhys update for all Dynamic nodes, for
 // either physics or scripted mode, based on current velocities.
+// Kinematic nodes are skipped, as they are only moved by script.
 func (gp *Group) WorldStepPhys(step float32) {
-	gp.WalkDown(func(k tree.Node) bool {
-		nii, _ := AsNode(k)
+	gp.WorldStepPhysParallel(step, 1)
+}
+
+// WorldStepPhysParallel does WorldStepPhys, with the independent subtrees
+// of each of the children of this group updated in parallel, using up
+// to the given number of goroutines, with results identical to WorldStepPhys.
+func (gp *Group) WorldStepPhysParallel(step float32, workers int) {
+	gp.walkDownParallel(workers, func(k tree.Node) bool {
+		nii, ni := AsNode(k)
 		if nii == nil {
 			return false // going into a different type of thing, bail
 		}
 		if !nii.IsDynamic() {
 			return false
 		}
+		if ni.IsKinematic() {
+			return true
+		}
 		nii.StepPhys(step)
 		return true
 	})
 
-	gp.WorldDynGroupBBox()
+	gp.WorldDynGroupBBoxParallel(workers)
+}
+
+// walkDownParallel calls fun on this group, and if it returns true, does
+// a WalkDown with fun on each of its children in parallel, using up to
+// the given number of goroutines, so fun must only modify the node it is
+// called on.
+func (gp *Group) walkDownParallel(workers int, fun func(k tree.Node) bool) {
+	if !fun(gp.This()) {
+		return
+	}
+	kids := gp.Kids
+	parallelFor(len(kids), workers, func(i int) {
+		kids[i].WalkDown(fun)
+	})
+}
+
+// walkDownPostParallel is the WalkDownPost version of walkDownParallel,
+// calling post on this group after its children are done.
+func (gp *Group) walkDownPostParallel(workers int, fun, post func(k tree.Node) bool) {
+	if !fun(gp.This()) {
+		return
+	}
+	kids := gp.Kids
+	parallelFor(len(kids), workers, func(i int) {
+		kids[i].WalkDownPost(fun, post)
+	})
+	post(gp.This())
 }
 
 const (
@@ -157,8 +212,15 @@ const (
 // If dynTop is true, then each Dynamic group is separate at the top level --
 // otherwise they are organized at the next group level.
 // Contacts are organized by dynamic group, when non-nil, for easier
-// processing.
+// processing.  Any Shared static geometry is included as another static group.
 func (gp *Group) WorldCollide(dynTop bool) []Contacts {
+	return gp.WorldCollideParallel(dynTop, 1)
+}
+
+// WorldCollideParallel does WorldCollide, with the contacts of each of the
+// dynamic groups found in parallel, using up to the given number of
+// goroutines, with results identical to WorldCollide.
+func (gp *Group) WorldCollideParallel(dynTop bool, workers int) []Contacts {
 	var stats []Node
 	var dyns []Node
 	for _, kid := range gp.Kids {
@@ -172,6 +234,9 @@ func (gp *Group) WorldCollide(dynTop bool) []Contacts {
 			stats = append(stats, nii)
 		}
 	}
+	if gp.Shared != nil {
+		stats = append(stats, gp.Shared)
+	}
 
 	var sdyns []Node
 	if !dynTop {
@@ -187,8 +252,9 @@ func (gp *Group) WorldCollide(dynTop bool) []Contacts {
 		dyns = sdyns
 	}
 
-	var cts []Contacts
-	for i, d := range dyns {
+	dcts := make([]Contacts, len(dyns))
+	parallelFor(len(dyns), workers, func(i int) {
+		d := dyns[i]
 		var dct Contacts
 		for _, s := range stats {
 			cc := BodyVelBBoxIntersects(d, s)
@@ -199,6 +265,10 @@ func (gp *Group) WorldCollide(dynTop bool) []Contacts {
 			cc := BodyVelBBoxIntersects(d, od)
 			dct = append(dct, cc...)
 		}
+		dcts[i] = dct
+	})
+	var cts []Contacts
+	for _, dct := range dcts {
 		if len(dct) > 0 {
 			cts = append(cts, dct)
 		}
@@ -206,6 +276,15 @@ func (gp *Group) WorldCollide(dynTop bool) []Contacts {
 	return cts
 }
 
+// walkShared does WalkDown with fun on this group, and then on its
+// Shared group, if any
+func (gp *Group) walkShared(fun func(k tree.Node) bool) {
+	gp.WalkDown(fun)
+	if gp.Shared != nil {
+		gp.Shared.WalkDown(fun)
+	}
+}
+
 // BodyPoint contains a Body and a Point on that body
 type BodyPoint struct {
 	Body  Body
@@ -213,10 +292,11 @@ type BodyPoint struct {
 }
 
 // RayBodyIntersections returns a list of bodies whose bounding box intersects
-// with the given ray, with the point of intersection
+// with the given ray, with the point of intersection.
+// Visual-only bodies (see SetNoCollide) are skipped.
 func (gp *Group) RayBodyIntersections(ray math32.Ray) []*BodyPoint {
 	var bs []*BodyPoint
-	gp.WalkDown(func(k tree.Node) bool {
+	gp.walkShared(func(k tree.Node) bool {
 		nii, ni := AsNode(k)
 		if nii == nil {
 			return false // going into a different type of thing, bail
@@ -229,6 +309,9 @@ func (gp *Group) RayBodyIntersections(ray math32.Ray) []*BodyPoint {
 			return true
 		}
 		bd := nii.AsBody()
+		if !Collides(bd) {
+			return false
+		}
 		bs = append(bs, &BodyPoint{bd, pt})
 		return false
 	})
@@ -0,0 +1,83 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package eve
+
+import (
+	"cogentcore.org/core/math32"
+)
+
+// Hull is a convex hull body shape, defined by a set of points in local
+// coordinates relative to its center of mass, which is mainly useful as a
+// collision Proxy that closely fits a detailed visual model.
+// The points do not need to be on the hull: interior points are ignored.
+type Hull struct {
+	BodyBase
+
+	// points in local coordinates whose convex hull is the shape
+	Points []math32.Vector3
+}
+
+// Bounds returns the bounding box of the Points, in local coordinates
+func (hl *Hull) Bounds() math32.Box3 {
+	bb := math32.B3Empty()
+	bb.ExpandByPoints(hl.Points)
+	if bb.IsEmpty() {
+		return math32.Box3{}
+	}
+	return bb
+}
+
+func (hl *Hull) SetBBox() {
+	lb := hl.LocalBBox()
+	hl.BBox.SetBounds(lb.Min, lb.Max)
+	hl.BBox.XForm(hl.Abs.Quat, hl.Abs.Pos)
+}
+
+func (hl *Hull) LocalBBox() math32.Box3 {
+	lb := hl.Bounds()
+	sc := hl.Abs.ScaleFactor()
+	return math32.Box3{Min: lb.Min.Mul(sc), Max: lb.Max.Mul(sc)}
+}
+
+func (hl *Hull) Support(dir math32.Vector3) math32.Vector3 {
+	var best math32.Vector3
+	bd := math32.Inf(-1)
+	for _, p := range hl.Points {
+		if d := p.Dot(dir); d > bd {
+			bd = d
+			best = p
+		}
+	}
+	return best
+}
+
+// SetInertia sets the Rigid.RotInertia for the current ScaledMass,
+// approximating the hull as a solid box of the size of its Bounds
+func (hl *Hull) SetInertia() {
+	m := hl.ScaledMass() / 12
+	sz := hl.Bounds().Size().Mul(hl.Abs.ScaleFactor())
+	sq := sz.Mul(sz)
+	hl.Rigid.SetShapeInertia(math32.Vec3(m*(sq.Y+sq.Z), m*(sq.X+sq.Z), m*(sq.X+sq.Y)))
+}
+
+func (hl *Hull) InitAbs(par *NodeBase) {
+	hl.InitAbsBase(par)
+	hl.SetInertia()
+	hl.SetBBox()
+	hl.BBox.VelNilProject()
+}
+
+func (hl *Hull) RelToAbs(par *NodeBase) {
+	hl.RelToAbsBase(par)
+	hl.SetInertia()
+	hl.SetBBox()
+	hl.BBox.VelProject(hl.Abs.LinVel, 1)
+}
+
+func (hl *Hull) StepPhys(step float32) {
+	hl.StepPhysBase(step)
+	hl.SetBBox()
+	hl.BBox.VelProject(hl.Abs.LinVel, step)
+}
@@ -0,0 +1,242 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package eve
+
+import (
+	"cogentcore.org/core/math32"
+)
+
+// IKLink is one link in an IK chain, which is rotated about its own
+// origin by changing its Rel.Quat
+type IKLink struct {
+
+	// the node that is rotated: a Group or Body in the chain
+	Node Node
+
+	// maximum rotation in degrees away from the Rest rotation -- 0 = unlimited
+	MaxAngle float32
+
+	// if nonzero, the link only rotates about this axis in its own local coordinates, as a hinge (e.g., an elbow) -- otherwise it rotates freely, as a ball joint
+	Axis math32.Vector3
+
+	// if true, the link is not rotated, e.g., for an intermediate group in the chain
+	Fixed bool
+
+	// the rest rotation that MaxAngle is relative to, from the Rel.Quat when the chain was made
+	Rest math32.Quat
+}
+
+// IK is an inverse kinematics solver for a chain of nested nodes (Groups
+// or Bodies) in the scripted style of updating, which adjusts the Rel.Quat
+// of each link so that the Tip of the End node reaches a target position
+// (Reach), or its Aim axis points toward a target (LookAt), e.g., for a
+// head and eyes that look at an object, or an arm that reaches toward it.
+// It uses cyclic coordinate descent (CCD), rotating each link in turn,
+// from the End toward the root, to bring the Tip as close as possible to
+// the target, within the MaxAngle limits and hinge Axis of each link.
+// As usual for scripted updates, the nodes in the chain must be Dynamic
+// (e.g., groups that contain Dynamic bodies) for their Abs poses to be
+// updated by WorldRelToAbs.
+type IK struct {
+
+	// the links of the chain, from the root to the End node, in order of nesting
+	Links []IKLink
+
+	// the end node of the chain (e.g., a hand or head), which is also the last of the Links
+	End Node
+
+	// the point in the local coordinates of the End node that reaches the target (e.g., the tip of a finger)
+	Tip math32.Vector3
+
+	// the axis in the local coordinates of the End node that is pointed at the target by LookAt
+	Aim math32.Vector3
+
+	// maximum number of iterations over the chain
+	Iters int `default:"20"`
+
+	// distance from the target within which the solution is done
+	Tol float32 `default:"0.001"`
+
+	// if non-nil, WorldRelToAbs is called on this world after solving -- leave nil when Solver.Step applies the updated Rel values
+	World *Group
+}
+
+// NewIK returns a new IK solver for the chain of nodes from root down to
+// end, which must be a descendant of root, with all of the nodes in the
+// chain as free links, resting at their current Rel.Quat, and
+// with default parameters.
+func NewIK(world *Group, root, end Node) *IK {
+	ik := &IK{End: end, World: world}
+	ik.Defaults()
+	for k := end; k != nil; {
+		nb := k.AsNodeBase()
+		ik.Links = append([]IKLink{{Node: k, Rest: nb.Rel.Quat}}, ik.Links...)
+		if k.This() == root.This() {
+			break
+		}
+		par, _ := AsNode(nb.Parent())
+		k = par
+	}
+	return ik
+}
+
+func (ik *IK) Defaults() {
+	ik.Aim = math32.Vec3(0, 0, -1)
+	ik.Iters = 20
+	ik.Tol = 0.001
+}
+
+// Link returns the link for given node, or nil if it is not in the chain
+func (ik *IK) Link(nd Node) *IKLink {
+	for i := range ik.Links {
+		if ik.Links[i].Node.This() == nd.This() {
+			return &ik.Links[i]
+		}
+	}
+	return nil
+}
+
+// Reach rotates the links so that the Tip of the End node reaches the
+// given target position in world coords, or comes as close to it as
+// possible, and returns the remaining distance from the target.
+func (ik *IK) Reach(target math32.Vector3) float32 {
+	return ik.solve(func(abs []Phys) (math32.Vector3, math32.Vector3) {
+		return ik.tip(abs), target
+	})
+}
+
+// LookAt rotates the links so that the Aim axis of the End node, from its
+// Tip, points toward the given target position in world coords, or as
+// close to it as possible, and returns the remaining angle in degrees
+// between the Aim axis and the direction to the target.
+func (ik *IK) LookAt(target math32.Vector3) float32 {
+	ik.solve(func(abs []Phys) (math32.Vector3, math32.Vector3) {
+		tip := ik.tip(abs)
+		dist := target.Sub(tip).Length()
+		return tip.Add(ik.aim(abs).MulScalar(dist)), target
+	})
+	abs := ik.chainAbs()
+	tip := ik.tip(abs)
+	d := target.Sub(tip)
+	if d.LengthSquared() == 0 {
+		return 0
+	}
+	return math32.RadToDeg(math32.Acos(math32.Clamp(ik.aim(abs).Dot(d.Normal()), -1, 1)))
+}
+
+// solve runs CCD, using given function that returns the current point
+// that is being moved and the target point for it, from the current Abs
+// poses of the links, and returns the remaining distance between them.
+func (ik *IK) solve(goal func(abs []Phys) (math32.Vector3, math32.Vector3)) float32 {
+	nl := len(ik.Links)
+	if nl == 0 {
+		return 0
+	}
+	abs := ik.chainAbs()
+	pt, tgt := goal(abs)
+	for iter := 0; iter < ik.Iters && pt.DistanceTo(tgt) > ik.Tol; iter++ {
+		for i := nl - 1; i >= 0; i-- {
+			lk := &ik.Links[i]
+			if lk.Fixed {
+				continue
+			}
+			piv := abs[i].Pos
+			a, b := pt.Sub(piv), tgt.Sub(piv)
+			var r math32.Quat
+			if lk.Axis != (math32.Vector3{}) {
+				ax := lk.Axis.MulQuat(abs[i].Quat).Normal()
+				a.SetSub(ax.MulScalar(a.Dot(ax)))
+				b.SetSub(ax.MulScalar(b.Dot(ax)))
+				if a.LengthSquared() < 1.0e-12 || b.LengthSquared() < 1.0e-12 {
+					continue
+				}
+				ang := math32.Atan2(a.Cross(b).Dot(ax), a.Dot(b))
+				r = math32.NewQuatAxisAngle(ax, ang)
+			} else {
+				if a.LengthSquared() < 1.0e-12 || b.LengthSquared() < 1.0e-12 {
+					continue
+				}
+				a.SetNormal()
+				b.SetNormal()
+				ax := a.Cross(b)
+				s := ax.Length()
+				if s < 1.0e-7 {
+					continue
+				}
+				r = math32.NewQuatAxisAngle(ax.DivScalar(s), math32.Atan2(s, a.Dot(b)))
+			}
+			par := ik.parentAbs(abs, i)
+			rq := r.Mul(abs[i].Quat)
+			nb := lk.Node.AsNodeBase()
+			nb.Rel.Quat = rq.Mul(par.Quat.Conjugate())
+			lk.limit()
+			ik.updateAbs(abs, i)
+			pt, tgt = goal(abs)
+		}
+	}
+	if ik.World != nil {
+		ik.World.WorldRelToAbs()
+	}
+	return pt.DistanceTo(tgt)
+}
+
+// limit limits the Rel.Quat of the link to MaxAngle from its Rest rotation
+func (lk *IKLink) limit() {
+	if lk.MaxAngle <= 0 {
+		return
+	}
+	nb := lk.Node.AsNodeBase()
+	d := nb.Rel.Quat.Mul(lk.Rest.Conjugate())
+	if d.W < 0 {
+		d.Set(-d.X, -d.Y, -d.Z, -d.W)
+	}
+	ang := 2 * math32.Acos(math32.Clamp(d.W, -1, 1))
+	mx := math32.DegToRad(lk.MaxAngle)
+	if ang <= mx {
+		return
+	}
+	ax := math32.Vec3(d.X, d.Y, d.Z).Normal()
+	lim := math32.NewQuatAxisAngle(ax, mx)
+	nb.Rel.Quat = lim.Mul(lk.Rest)
+}
+
+// parentAbs returns the Abs pose of the parent of link i
+func (ik *IK) parentAbs(abs []Phys, i int) Phys {
+	if i > 0 {
+		return abs[i-1]
+	}
+	if par, pn := AsNode(ik.Links[0].Node.AsNodeBase().Parent()); par != nil {
+		return pn.relWorld()
+	}
+	return Phys{Quat: math32.NewQuat(0, 0, 0, 1)}
+}
+
+// chainAbs returns the Abs poses of the links implied by their current Rel values
+func (ik *IK) chainAbs() []Phys {
+	abs := make([]Phys, len(ik.Links))
+	ik.updateAbs(abs, 0)
+	return abs
+}
+
+// updateAbs updates the Abs poses of the links from link i on down,
+// from their current Rel values
+func (ik *IK) updateAbs(abs []Phys, i int) {
+	for ; i < len(ik.Links); i++ {
+		par := ik.parentAbs(abs, i)
+		rel := ik.Links[i].Node.AsNodeBase().Rel
+		abs[i].FromRel(&rel, &par)
+	}
+}
+
+// tip returns the world position of the Tip for given link poses
+func (ik *IK) tip(abs []Phys) math32.Vector3 {
+	end := abs[len(abs)-1]
+	return ik.Tip.Mul(end.ScaleFactor()).MulQuat(end.Quat).Add(end.Pos)
+}
+
+// aim returns the world direction of the Aim axis for given link poses
+func (ik *IK) aim(abs []Phys) math32.Vector3 {
+	return ik.Aim.MulQuat(abs[len(abs)-1].Quat).Normal()
+}
@@ -0,0 +1,318 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package eve
+
+import (
+	"cogentcore.org/core/math32"
+)
+
+// MaxManifoldPoints is the maximum number of points in a contact Manifold,
+// which is sufficient to support a box resting on one of its faces.
+const MaxManifoldPoints = 4
+
+// BodyPair is the key for the contact Manifold between two bodies,
+// including the Offset of B, so that the contacts between the same
+// bodies across the seams of a wrap-around world (see Bounds) each
+// have their own Manifold.
+type BodyPair struct {
+	A, B Body
+
+	// offset of B from its position in the world, from Contact.Offset
+	Offset math32.Vector3
+}
+
+// ManifoldPoint is one point of contact in a Manifold, which persists
+// across steps as long as the bodies remain in contact at that point,
+// along with the impulses applied there on the last step, which are
+// used to warm start the Solver on the next step.
+type ManifoldPoint struct {
+
+	// contact point on A, in the local coordinates of A
+	LocalA math32.Vector3
+
+	// contact point on B, in the local coordinates of B
+	LocalB math32.Vector3
+
+	// normal pointing from B to A, in the local coordinates of B
+	LocalNormB math32.Vector3
+
+	// contact point on A in world coordinates
+	PtA math32.Vector3
+
+	// contact point on B in world coordinates
+	PtB math32.Vector3
+
+	// normal pointing from B to A in world coordinates
+	NormB math32.Vector3
+
+	// signed distance between PtB and PtA along NormB: negative if overlapping
+	Dist float32
+
+	// identifies the feature of the shape of A at the contact point (e.g., a box vertex), or 0 if none (see ShapeFeature)
+	FeatureA uint32
+
+	// identifies the feature of the shape of B at the contact point, or 0 if none
+	FeatureB uint32
+
+	// accumulated normal impulse applied on the last step
+	ImpN float32
+
+	// accumulated friction impulse applied on the last step, in world coordinates
+	ImpT math32.Vector3
+
+	// accumulated rolling friction angular impulse applied on the last step
+	ImpRoll math32.Vector3
+
+	// accumulated spinning friction angular impulse applied on the last step
+	ImpSpin float32
+
+	// number of steps that this point has persisted
+	Age int
+}
+
+// Manifold is the persistent set of contact points between two bodies,
+// which is updated on each step from the narrow-phase ShapeDist contact,
+// keeping up to MaxManifoldPoints points that cover the largest area.
+// Points that were generated on previous steps are moved along with the
+// bodies, and are dropped when the bodies separate or slide apart there.
+type Manifold struct {
+
+	// one body
+	A Body
+
+	// the other body
+	B Body
+
+	// offset of B from its position in the world, from Contact.Offset
+	Offset math32.Vector3
+
+	// current contact points
+	Points []ManifoldPoint
+}
+
+// NewManifold returns a new empty Manifold for given bodies,
+// with B at given offset from its position in the world
+func NewManifold(a, b Body, off math32.Vector3) *Manifold {
+	return &Manifold{A: a, B: b, Offset: off, Points: make([]ManifoldPoint, 0, MaxManifoldPoints)}
+}
+
+// Update updates the manifold from the given contact for the same bodies
+// and Offset, with UpdtDist already computed, as the first step of
+// ResolveContacts.
+// Existing points are refreshed for the current body poses and dropped
+// if beyond the breakDist distance, and the new contact point is added.
+// If the bodies are touching, and there are fewer than MaxManifoldPoints,
+// then additional points are generated by perturbing the orientation of
+// the smaller body, so that a full manifold is available immediately.
+func (mf *Manifold) Update(c *Contact, breakDist float32) {
+	mf.Refresh(breakDist)
+	mf.AddPoint(mf.NewPoint(c.PtA(), c.PtB, c.NormB, c.Dist), breakDist)
+	if c.Dist < breakDist && len(mf.Points) < MaxManifoldPoints {
+		mf.perturb(c, breakDist)
+	}
+}
+
+// NewPoint returns a new ManifoldPoint for given world coordinate points
+// on A and B, normal from B to A, and signed distance
+func (mf *Manifold) NewPoint(ptA, ptB, norm math32.Vector3, dist float32) ManifoldPoint {
+	na := mf.A.AsNodeBase()
+	nb := mf.B.AsNodeBase()
+	iqa := na.Abs.Quat.Conjugate()
+	iqb := nb.Abs.Quat.Conjugate()
+	mp := ManifoldPoint{PtA: ptA, PtB: ptB, NormB: norm, Dist: dist}
+	mp.LocalA = ptA.Sub(na.Abs.Pos).MulQuat(iqa)
+	mp.LocalB = ptB.Sub(nb.Abs.Pos.Add(mf.Offset)).MulQuat(iqb)
+	mp.LocalNormB = norm.MulQuat(iqb)
+	mp.FeatureA = ShapeFeature(mf.A, mp.LocalA)
+	mp.FeatureB = ShapeFeature(mf.B, mp.LocalB)
+	return mp
+}
+
+// Refresh updates the world coordinates of the existing points from the
+// current poses of the bodies, and removes points where the bodies have
+// separated by more than breakDist along the normal, or slid apart by
+// more than breakDist perpendicular to it.
+func (mf *Manifold) Refresh(breakDist float32) {
+	na := mf.A.AsNodeBase()
+	nb := mf.B.AsNodeBase()
+	np := mf.Points[:0]
+	for _, mp := range mf.Points {
+		mp.PtA = mp.LocalA.MulQuat(na.Abs.Quat).Add(na.Abs.Pos)
+		mp.PtB = mp.LocalB.MulQuat(nb.Abs.Quat).Add(nb.Abs.Pos).Add(mf.Offset)
+		mp.NormB = mp.LocalNormB.MulQuat(nb.Abs.Quat)
+		d := mp.PtA.Sub(mp.PtB)
+		mp.Dist = d.Dot(mp.NormB)
+		if mp.Dist > breakDist {
+			continue
+		}
+		drift := d.Sub(mp.NormB.MulScalar(mp.Dist))
+		if drift.LengthSquared() > breakDist*breakDist {
+			continue
+		}
+		mp.Age++
+		np = append(np, mp)
+	}
+	mf.Points = np
+}
+
+// AddPoint adds given point to the manifold.  If it matches an existing
+// point, by having the same distinct shape features, or being within
+// matchDist of it, then it replaces that point, retaining its impulses.
+// Otherwise, if the manifold is full, it replaces the point that results
+// in the largest contact area, always keeping the deepest point.
+func (mf *Manifold) AddPoint(mp ManifoldPoint, matchDist float32) {
+	if i := mf.match(&mp, matchDist); i >= 0 {
+		op := &mf.Points[i]
+		mp.ImpN, mp.ImpT, mp.ImpRoll, mp.ImpSpin, mp.Age = op.ImpN, op.ImpT, op.ImpRoll, op.ImpSpin, op.Age
+		*op = mp
+		return
+	}
+	if len(mf.Points) < MaxManifoldPoints {
+		mf.Points = append(mf.Points, mp)
+		return
+	}
+	mf.Points[mf.replaceIndex(&mp)] = mp
+}
+
+// match returns the index of the existing point that matches given one, or -1
+func (mf *Manifold) match(mp *ManifoldPoint, matchDist float32) int {
+	for i := range mf.Points {
+		op := &mf.Points[i]
+		if (mp.FeatureA != 0 || mp.FeatureB != 0) && mp.FeatureA == op.FeatureA && mp.FeatureB == op.FeatureB {
+			return i
+		}
+	}
+	best := -1
+	bd := matchDist * matchDist
+	for i := range mf.Points {
+		if d := mf.Points[i].LocalA.DistanceToSquared(mp.LocalA); d < bd {
+			best = i
+			bd = d
+		}
+	}
+	return best
+}
+
+// replaceIndex returns the index of the point in a full manifold to replace
+// with given point, which maximizes the area of the resulting contact
+// points, while keeping the deepest existing point if it is deeper than mp.
+func (mf *Manifold) replaceIndex(mp *ManifoldPoint) int {
+	deep := -1
+	md := mp.Dist
+	for i := range mf.Points {
+		if mf.Points[i].Dist < md {
+			deep = i
+			md = mf.Points[i].Dist
+		}
+	}
+	best := 0
+	var barea float32 = -1
+	var pts [MaxManifoldPoints]math32.Vector3
+	for i := range mf.Points {
+		if i == deep {
+			continue
+		}
+		for j := range mf.Points {
+			pts[j] = mf.Points[j].PtA
+		}
+		pts[i] = mp.PtA
+		area := pts[2].Sub(pts[0]).Cross(pts[3].Sub(pts[1])).LengthSquared()
+		if area > barea {
+			best = i
+			barea = area
+		}
+	}
+	return best
+}
+
+// perturb adds contact points computed by rotating the smaller body by
+// a small angle around axes perpendicular to the normal of the contact,
+// in 4 different directions, and then transforming the resulting contact
+// point back to the original orientation of the body.  This finds the
+// corners of faces in contact, which the single deepest point does not.
+func (mf *Manifold) perturb(c *Contact, breakDist float32) {
+	pa := bodyPose(mf.A)
+	pb := bodyPose(mf.B)
+	pb.pos.SetAdd(mf.Offset)
+	if _, ok := mf.A.(*Sphere); ok {
+		return
+	}
+	if _, ok := mf.B.(*Sphere); ok {
+		return
+	}
+	ra := supportRadius(mf.A)
+	rb := supportRadius(mf.B)
+	pertA := ra <= rb
+	rad := min(ra, rb)
+	if rad <= 0 {
+		return
+	}
+	ang := min(breakDist/rad, 0.125*math32.Pi)
+	n := c.NormB
+	t := tangentTo(n)
+	for i := 0; i < 4; i++ {
+		rn := math32.NewQuatAxisAngle(n, float32(i)*0.5*math32.Pi)
+		r := math32.NewQuatAxisAngle(t.MulQuat(rn), ang)
+		ir := r.Conjugate()
+		ppa, ppb := pa, pb
+		if pertA {
+			ppa.quat = r.Mul(pa.quat)
+		} else {
+			ppb.quat = r.Mul(pb.quat)
+		}
+		_, ptA, ptB, _ := shapeDist(&ppa, &ppb)
+		if pertA {
+			ptA = ptA.Sub(pa.pos).MulQuat(ir).Add(pa.pos)
+		} else {
+			ptB = ptB.Sub(pb.pos).MulQuat(ir).Add(pb.pos)
+		}
+		dist := ptA.Sub(ptB).Dot(n)
+		if dist > breakDist {
+			continue
+		}
+		if pertA {
+			ptB = ptA.Sub(n.MulScalar(dist))
+		} else {
+			ptA = ptB.Add(n.MulScalar(dist))
+		}
+		mf.AddPoint(mf.NewPoint(ptA, ptB, n, dist), breakDist)
+	}
+}
+
+// supportRadius returns the distance from the center of the body to the
+// corner of the bounding box of its collision shape, in local coordinates.
+func supportRadius(bod Body) float32 {
+	ext := math32.Vec3(bod.Support(math32.Vec3(1, 0, 0)).X, bod.Support(math32.Vec3(0, 1, 0)).Y, bod.Support(math32.Vec3(0, 0, 1)).Z)
+	return ext.Mul(bod.AsNodeBase().Abs.ScaleFactor()).Length()
+}
+
+// ShapeFeature returns an identifier for the distinct feature of the
+// collision shape of given body at given point in local coordinates,
+// or 0 if the point is not at a distinct feature.  This is used to
+// match contact points across steps.  Currently the 8 vertices of a Box
+// are identified, as 1 + bits for the positive X, Y, Z coordinates.
+func ShapeFeature(bod Body, local math32.Vector3) uint32 {
+	bx, ok := bod.(*Box)
+	if !ok {
+		return 0
+	}
+	hs := bx.Size.MulScalar(.5).Mul(bx.Abs.ScaleFactor())
+	tol := 0.02 * min(hs.X, hs.Y, hs.Z)
+	ad := local.Abs().Sub(hs).Abs()
+	if ad.X > tol || ad.Y > tol || ad.Z > tol {
+		return 0
+	}
+	var id uint32 = 1
+	if local.X > 0 {
+		id += 1
+	}
+	if local.Y > 0 {
+		id += 2
+	}
+	if local.Z > 0 {
+		id += 4
+	}
+	return id
+}
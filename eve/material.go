@@ -0,0 +1,198 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package eve
+
+// Surface has the properties of a surface that determine the response
+// to contact with another surface, for a single material or for the
+// combination of two materials in contact.
+type Surface struct {
+
+	// dynamic friction coefficient -- how much friction is generated by transverse (sliding) motion
+	Friction float32
+
+	// static friction coefficient -- how much transverse force can be resisted without sliding -- if 0, Friction is used
+	StaticFriction float32
+
+	// COR or coefficient of restitution -- how elastic is the collision i.e., final velocity / initial velocity
+	Bounce float32 `min:"0" max:"1"`
+
+	// rolling friction coefficient, in units of length (the lever arm of the resisting torque relative to the normal force) -- resists rolling, mainly relevant for spheres and cylinders
+	RollFriction float32
+
+	// spinning friction coefficient, in units of length -- resists spinning about the contact normal
+	SpinFriction float32
+}
+
+// Static returns the StaticFriction, or Friction if that is 0
+func (sf *Surface) Static() float32 {
+	if sf.StaticFriction == 0 {
+		return sf.Friction
+	}
+	return sf.StaticFriction
+}
+
+// Material specifies the surface properties of a named material,
+// such as ice, rubber or wood, which bodies can reference by name
+// via Rigid.Material.
+type Material struct {
+
+	// name of the material -- used as the key in the Materials table
+	Name string
+
+	// surface properties of the material
+	Surface
+}
+
+// Materials is a table of named materials, with optional pairwise
+// overrides, and the rules for combining the surface properties
+// of two bodies that come into contact.
+type Materials struct {
+
+	// rule for combining dynamic and static friction values of two bodies
+	FrictionCombine CombineModes
+
+	// rule for combining rolling and spinning friction values of two bodies, which are in units of length, so that multiplying them would not make sense
+	RollCombine CombineModes
+
+	// rule for combining bounce values of two bodies
+	BounceCombine CombineModes
+
+	// materials by name
+	Mats map[string]*Material
+
+	// pairwise overrides, keyed by material names -- use SetPair to add, which ensures that the order of names does not matter
+	Pairs map[MaterialKey]*Surface
+}
+
+// MaterialKey is the key for a pair of materials, with names in sorted order
+type MaterialKey struct {
+	A, B string
+}
+
+// NewMaterialKey returns the key for given two materials, in sorted order
+func NewMaterialKey(a, b string) MaterialKey {
+	if b < a {
+		a, b = b, a
+	}
+	return MaterialKey{A: a, B: b}
+}
+
+// Defaults sets default combine rules: the geometric-style multiply for
+// friction (so that ice on anything stays slippery), max for rolling
+// and spinning friction (so that a ball stops on a rough floor even if
+// the ball itself has none), and max for bounce (so that a rubber ball
+// bounces on any surface).
+func (mt *Materials) Defaults() {
+	mt.FrictionCombine = CombineMultiply
+	mt.RollCombine = CombineMax
+	mt.BounceCombine = CombineMax
+}
+
+// Add adds a new material with given friction and bounce to the table,
+// replacing any existing one of the same name.  Other surface properties
+// can be set on the returned Material.
+func (mt *Materials) Add(name string, friction, bounce float32) *Material {
+	if mt.Mats == nil {
+		mt.Mats = make(map[string]*Material)
+	}
+	m := &Material{Name: name}
+	m.Friction = friction
+	m.Bounce = bounce
+	mt.Mats[name] = m
+	return m
+}
+
+// Material returns the material of given name, nil if not found
+func (mt *Materials) Material(name string) *Material {
+	if mt.Mats == nil {
+		return nil
+	}
+	return mt.Mats[name]
+}
+
+// SetPair sets pairwise friction and bounce values for contacts between
+// the two given materials, which are used instead of the combine rules.
+// Other surface properties can be set on the returned Surface.
+func (mt *Materials) SetPair(a, b string, friction, bounce float32) *Surface {
+	if mt.Pairs == nil {
+		mt.Pairs = make(map[MaterialKey]*Surface)
+	}
+	sf := &Surface{Friction: friction, Bounce: bounce}
+	mt.Pairs[NewMaterialKey(a, b)] = sf
+	return sf
+}
+
+// Pair returns the pairwise override for given materials, nil if none
+func (mt *Materials) Pair(a, b string) *Surface {
+	if mt.Pairs == nil {
+		return nil
+	}
+	return mt.Pairs[NewMaterialKey(a, b)]
+}
+
+// Props returns the surface properties for given rigid body properties,
+// using the named Material if it is in the table, and otherwise the
+// values on the Rigid itself.
+func (mt *Materials) Props(rg *Rigid) Surface {
+	if m := mt.Material(rg.Material); m != nil {
+		return m.Surface
+	}
+	return rg.Surface()
+}
+
+// Combine returns the surface properties to use for a contact between
+// two bodies with given rigid properties, using any pairwise override
+// for their materials, and otherwise the combine rules.
+func (mt *Materials) Combine(a, b *Rigid) Surface {
+	if a.Material != "" && b.Material != "" {
+		if sf := mt.Pair(a.Material, b.Material); sf != nil {
+			return *sf
+		}
+	}
+	sa := mt.Props(a)
+	sb := mt.Props(b)
+	fc := mt.FrictionCombine
+	rc := mt.RollCombine
+	return Surface{
+		Friction:       fc.Combine(sa.Friction, sb.Friction),
+		StaticFriction: fc.Combine(sa.Static(), sb.Static()),
+		Bounce:         mt.BounceCombine.Combine(sa.Bounce, sb.Bounce),
+		RollFriction:   rc.Combine(sa.RollFriction, sb.RollFriction),
+		SpinFriction:   rc.Combine(sa.SpinFriction, sb.SpinFriction),
+	}
+}
+
+//////////////////////////////////////////////////////////////////////
+// CombineModes
+
+// CombineModes are the ways of combining a material property from two bodies in contact
+type CombineModes int32 //enums:enum
+
+const (
+	// CombineAverage uses the average of the two values
+	CombineAverage CombineModes = iota
+
+	// CombineMin uses the smaller of the two values
+	CombineMin
+
+	// CombineMax uses the larger of the two values
+	CombineMax
+
+	// CombineMultiply uses the product of the two values
+	CombineMultiply
+)
+
+// Combine returns the combination of two values according to the mode
+func (cm CombineModes) Combine(a, b float32) float32 {
+	switch cm {
+	case CombineMin:
+		return min(a, b)
+	case CombineMax:
+		return max(a, b)
+	case CombineMultiply:
+		return a * b
+	}
+	return 0.5 * (a + b)
+}
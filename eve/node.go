@@ -15,7 +15,7 @@ import (
 type Node interface {
 	tree.Node
 
-	// EveNodeType returns the type of node this is (Body, Group, Joint)
+	// EveNodeType returns the type of node this is (Body, Group, Joint, Soft, Particles)
 	EveNodeType() NodeTypes
 
 	// AsNodeBase returns a generic NodeBase for our node -- gives generic
@@ -57,7 +57,7 @@ type Node interface {
 
 // NodeBase is the basic eve node, which has position, rotation, velocity
 // and computed bounding boxes, etc.
-// There are only three different kinds of Nodes: Group, Body, and Joint
+// There are only five different kinds of Nodes: Group, Body, Joint, Soft, and Particles
 type NodeBase struct {
 	tree.NodeBase
 
@@ -86,6 +86,11 @@ func (nb *NodeBase) IsDynamic() bool {
 	return nb.Is(Dynamic)
 }
 
+// IsKinematic returns true if node has the Kinematic flag set
+func (nb *NodeBase) IsKinematic() bool {
+	return nb.Is(Kinematic)
+}
+
 // SetInitPos sets the initial position
 func (nb *NodeBase) SetInitPos(pos math32.Vector3) *NodeBase {
 	nb.Initial.Pos = pos
@@ -98,6 +103,12 @@ func (nb *NodeBase) SetInitQuat(quat math32.Quat) *NodeBase {
 	return nb
 }
 
+// SetInitScale sets the initial scale factors along the local axes
+func (nb *NodeBase) SetInitScale(scale math32.Vector3) *NodeBase {
+	nb.Initial.Scale = scale
+	return nb
+}
+
 // SetInitLinVel sets the initial linear velocity
 func (nb *NodeBase) SetInitLinVel(vel math32.Vector3) *NodeBase {
 	nb.Initial.LinVel = vel
@@ -116,9 +127,7 @@ func (nb *NodeBase) SetInitAngVel(vel math32.Vector3) *NodeBase {
 // Body nodes should also set their bounding boxes.
 // Called in a FuncDownMeFirst traversal.
 func (nb *NodeBase) InitAbsBase(par *NodeBase) {
-	if nb.Initial.Quat.IsNil() {
-		nb.Initial.Quat.SetIdentity()
-	}
+	nb.Initial.Defaults()
 	nb.Rel = nb.Initial
 	if par != nil {
 		nb.Abs.FromRel(&nb.Initial, &par.Abs)
@@ -136,14 +145,26 @@ func (nb *NodeBase) InitAbsBase(par *NodeBase) {
 // It is passed the parent (nil = top).
 // Body nodes should also update their bounding boxes.
 // Called in a FuncDownMeFirst traversal.
+// Bodies that are moved by physics (see IsMovable) retain their
+// current Abs velocities, and other bodies also record the change in
+// rotation, for use in the Solver.
 func (nb *NodeBase) RelToAbsBase(par *NodeBase) {
-	ppos := nb.Abs.Pos
+	prv := nb.Abs
 	if par != nil {
 		nb.Abs.FromRel(&nb.Rel, &par.Abs)
 	} else {
 		nb.Abs = nb.Rel
 	}
-	nb.Abs.LinVel = nb.Abs.Pos.Sub(ppos) // needed for VelBBox prjn
+	bod := nb.This().(Node).AsBody()
+	if bod != nil && IsMovable(bod) {
+		nb.Abs.LinVel = prv.LinVel
+		nb.Abs.AngVel = prv.AngVel
+		return
+	}
+	nb.Abs.LinVel = nb.Abs.Pos.Sub(prv.Pos) // needed for VelBBox prjn
+	if bod != nil {
+		bod.AsBodyBase().scriptAngVel = QuatDelta(prv.Quat, nb.Abs.Quat)
+	}
 }
 
 // StepPhysBase is base-level version of StepPhys -- most nodes call this.
@@ -155,6 +176,147 @@ func (nb *NodeBase) RelToAbsBase(par *NodeBase) {
 func (nb *NodeBase) StepPhysBase(step float32) {
 	nb.Abs.StepByAngVel(step)
 	nb.Abs.StepByLinVel(step)
+	nb.relFromAbs()
+}
+
+// relFromAbs updates the Rel position and rotation from the current
+// Abs values, relative to the parent, so that the Rel values (which are
+// used by the views) reflect the results of StepPhys.
+func (nb *NodeBase) relFromAbs() {
+	_, pi := AsNode(nb.Parent())
+	if pi == nil {
+		nb.Rel.Pos = nb.Abs.Pos
+		nb.Rel.Quat = nb.Abs.Quat
+		return
+	}
+	nb.Rel.Quat = pi.LocalQuat(nb.Abs.Quat)
+	nb.Rel.Pos = pi.LocalPoint(nb.Abs.Pos)
+}
+
+// shiftAbs moves this node and all of its children by given offset in
+// world coords, updating their Abs positions and bounding boxes directly,
+// along with the Rel position of this node, without affecting velocities.
+// Group bounding boxes must be updated after, e.g., with WorldDynGroupBBox.
+func (nb *NodeBase) shiftAbs(off math32.Vector3) {
+	nb.addRelPos(off)
+	nb.WalkDown(func(k tree.Node) bool {
+		_, ni := AsNode(k)
+		if ni == nil {
+			return false
+		}
+		ni.Abs.Pos.SetAdd(off)
+		ni.BBox.Translate(off)
+		return true
+	})
+}
+
+// addRelPos adds given offset in world coords to the Rel position,
+// converting it into the coordinates of the parent.
+func (nb *NodeBase) addRelPos(off math32.Vector3) {
+	if _, pi := AsNode(nb.Parent()); pi != nil {
+		off = off.MulQuat(pi.Abs.Quat.Conjugate()).Div(pi.Abs.ScaleFactor())
+	}
+	nb.Rel.Pos.SetAdd(off)
+}
+
+// relWorld returns the position and orientation in world coords implied
+// by the current Rel values of this node and its Dynamic parents,
+// which is what WorldRelToAbs will set for its Abs values.
+func (nb *NodeBase) relWorld() Phys {
+	var chain []*NodeBase
+	ps := Phys{Quat: math32.NewQuat(0, 0, 0, 1)}
+	for k := nb.This(); k != nil; k = k.Parent() {
+		nii, ni := AsNode(k)
+		if nii == nil {
+			break
+		}
+		if ni != nb && !nii.IsDynamic() {
+			ps = ni.Abs
+			break
+		}
+		chain = append(chain, ni)
+	}
+	for i := len(chain) - 1; i >= 0; i-- {
+		rel := chain[i].Rel
+		par := ps
+		ps.FromRel(&rel, &par)
+	}
+	ps.LinVel, ps.AngVel = math32.Vector3{}, math32.Vector3{}
+	return ps
+}
+
+///////////////////////////////////////////////////////
+// 	Frames
+
+// WorldPoint returns the given point in the local coordinates of this
+// node (e.g., a point on the surface of a body) in world coords,
+// according to its current Abs pose.
+func (nb *NodeBase) WorldPoint(pt math32.Vector3) math32.Vector3 {
+	return pt.Mul(nb.Abs.ScaleFactor()).MulQuat(nb.Abs.Quat).Add(nb.Abs.Pos)
+}
+
+// LocalPoint returns the given point in world coords in the local
+// coordinates of this node, according to its current Abs pose,
+// as the inverse of WorldPoint.
+func (nb *NodeBase) LocalPoint(pt math32.Vector3) math32.Vector3 {
+	return pt.Sub(nb.Abs.Pos).MulQuat(nb.Abs.Quat.Conjugate()).Div(nb.Abs.ScaleFactor())
+}
+
+// WorldDir returns the given direction in the local coordinates of this
+// node (e.g., 0,0,-1 for the direction that a camera looks) in world
+// coords, according to its current Abs rotation, without any scaling.
+func (nb *NodeBase) WorldDir(dir math32.Vector3) math32.Vector3 {
+	return dir.MulQuat(nb.Abs.Quat)
+}
+
+// LocalDir returns the given direction in world coords in the local
+// coordinates of this node, according to its current Abs rotation,
+// without any scaling, as the inverse of WorldDir.
+func (nb *NodeBase) LocalDir(dir math32.Vector3) math32.Vector3 {
+	return dir.MulQuat(nb.Abs.Quat.Conjugate())
+}
+
+// WorldQuat returns the given rotation relative to the local frame of
+// this node in world coords, according to its current Abs rotation.
+func (nb *NodeBase) WorldQuat(q math32.Quat) math32.Quat {
+	return q.Mul(nb.Abs.Quat)
+}
+
+// LocalQuat returns the given rotation in world coords relative to the
+// local frame of this node, according to its current Abs rotation,
+// as the inverse of WorldQuat.
+func (nb *NodeBase) LocalQuat(q math32.Quat) math32.Quat {
+	return q.Mul(nb.Abs.Quat.Conjugate())
+}
+
+// PoseOf returns the current Abs pose of the other node expressed in the
+// local frame of this node, i.e., the Rel values that it would have as a
+// child of this node, including its velocities relative to this node
+// (see Phys.ToRel), e.g., for the position and heading of a target
+// relative to an agent.
+func (nb *NodeBase) PoseOf(other Node) Phys {
+	var ps Phys
+	ps.ToRel(&other.AsNodeBase().Abs, &nb.Abs)
+	return ps
+}
+
+// SetWorldPose sets the Rel position and rotation of this node so that
+// its Abs pose in world coords has the given position and rotation, given
+// the pose of its parent implied by the current Rel values of its Dynamic
+// parents (see WorldRelToAbs), so that parents can be posed first.
+// The Abs values are then updated by WorldRelToAbs, which is called by
+// Solver.Step, or can be called directly.
+func (nb *NodeBase) SetWorldPose(pos math32.Vector3, quat math32.Quat) *NodeBase {
+	abs := Phys{Pos: pos, Quat: quat}
+	par := Phys{Quat: math32.NewQuat(0, 0, 0, 1)}
+	if _, pi := AsNode(nb.Parent()); pi != nil {
+		par = pi.relWorld()
+	}
+	var rel Phys
+	rel.ToRel(&abs, &par)
+	nb.Rel.Pos = rel.Pos
+	nb.Rel.Quat = rel.Quat
+	return nb
 }
 
 // AsNode converts Ki to a Node interface and a Node3DBase obj -- nil if not.
@@ -180,6 +342,8 @@ const (
 	BODY NodeTypes = iota
 	GROUP
 	JOINT
+	SOFT
+	PARTICLES
 )
 
 //////////////////////////////////////////////////////////////////////
@@ -191,7 +355,26 @@ type NodeFlags tree.Flags //enums:bitflag
 const (
 	// Dynamic means that this node can move -- if not so marked, it is
 	// a Static node.  Any top-level group that is not Dynamic is immediately
-	// pruned from further consideration, so top-level groups should be
-	// separated into Dynamic and Static nodes at the start.
+	// pruned from further consideration in WorldCollide, so top-level groups
+	// should be separated into Dynamic and Static nodes at the start when
+	// using it.  SpatialHash (WorldCollideAll) works for any layout.
 	Dynamic NodeFlags = NodeFlags(tree.FlagsN) + iota
+
+	// Kinematic means that this body is moved only by script, by setting
+	// Rel values and calling WorldRelToAbs, and not by physics.
+	// Its velocities are inferred from its motion, and it has infinite
+	// mass in the Solver, so that it pushes movable bodies out of its way.
+	// Kinematic bodies must also be Dynamic (see SetKinematic).
+	Kinematic
+
+	// Proxy means that the shape of this body is an invisible collision
+	// proxy, e.g., a simple Capsule for a detailed agent model: the views
+	// do not draw its shape, and instead show its Vis library entry as is,
+	// without sizing it to the shape (see SetProxy).
+	Proxy
+
+	// NoCollide means that this body is a purely visual decoration, which
+	// is shown in the views but is skipped in all collision detection
+	// and collision queries (see SetNoCollide).
+	NoCollide
 )
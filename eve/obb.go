@@ -0,0 +1,102 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package eve
+
+import "cogentcore.org/core/math32"
+
+// OBB is an oriented bounding box in world coords, which remains tight
+// around a rotated body, unlike the axis-aligned BBox, which can be much
+// larger than the body (e.g., for a long wall rotated 45 degrees).
+type OBB struct {
+
+	// center of the box in world coords
+	Center math32.Vector3
+
+	// half of the size of the box along each of its local axes
+	HalfSize math32.Vector3
+
+	// orientation of the box in world coords
+	Quat math32.Quat
+}
+
+// Set sets the OBB from given bounds in local coords of a body
+// with given orientation and position in world coords
+func (ob *OBB) Set(box math32.Box3, q math32.Quat, pos math32.Vector3) {
+	ob.Center = box.Center().MulQuat(q).Add(pos)
+	ob.HalfSize = box.Size().MulScalar(.5)
+	ob.Quat = q
+}
+
+// Axes returns the local X, Y, Z axes of the box in world coords
+func (ob *OBB) Axes() [3]math32.Vector3 {
+	return [3]math32.Vector3{math32.Vec3(1, 0, 0).MulQuat(ob.Quat), math32.Vec3(0, 1, 0).MulQuat(ob.Quat), math32.Vec3(0, 0, 1).MulQuat(ob.Quat)}
+}
+
+// Radius returns the radius of the sphere around the Center that contains the box
+func (ob *OBB) Radius() float32 {
+	return ob.HalfSize.Length()
+}
+
+// AABB returns the axis-aligned bounding box containing the box
+func (ob *OBB) AABB() math32.Box3 {
+	hs := math32.Box3{Min: ob.HalfSize.Negate(), Max: ob.HalfSize}
+	return hs.MulQuat(ob.Quat).Translate(ob.Center)
+}
+
+// Sweep returns the OBB, with the same orientation, that contains
+// this box and the box translated by given displacement in world coords
+func (ob *OBB) Sweep(disp math32.Vector3) OBB {
+	ld := disp.MulQuat(ob.Quat.Conjugate()).Abs()
+	return OBB{Center: ob.Center.Add(disp.MulScalar(.5)), HalfSize: ob.HalfSize.Add(ld.MulScalar(.5)), Quat: ob.Quat}
+}
+
+// Intersects returns true if this box intersects the other box,
+// using the separating axis test, after first testing the bounding spheres.
+// See Ericson, Real-Time Collision Detection, 4.4.1.
+func (ob *OBB) Intersects(oth *OBB) bool {
+	t := oth.Center.Sub(ob.Center)
+	rs := ob.Radius() + oth.Radius()
+	if t.LengthSquared() > rs*rs {
+		return false
+	}
+	a := ob.Axes()
+	b := oth.Axes()
+	ea := [3]float32{ob.HalfSize.X, ob.HalfSize.Y, ob.HalfSize.Z}
+	eb := [3]float32{oth.HalfSize.X, oth.HalfSize.Y, oth.HalfSize.Z}
+	const eps = 1.0e-6 // avoids false separation for parallel edges
+	var r, ar [3][3]float32
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			r[i][j] = a[i].Dot(b[j])
+			ar[i][j] = math32.Abs(r[i][j]) + eps
+		}
+	}
+	tl := [3]float32{t.Dot(a[0]), t.Dot(a[1]), t.Dot(a[2])}
+	for i := 0; i < 3; i++ {
+		rb := eb[0]*ar[i][0] + eb[1]*ar[i][1] + eb[2]*ar[i][2]
+		if math32.Abs(tl[i]) > ea[i]+rb {
+			return false
+		}
+	}
+	for j := 0; j < 3; j++ {
+		ra := ea[0]*ar[0][j] + ea[1]*ar[1][j] + ea[2]*ar[2][j]
+		if math32.Abs(tl[0]*r[0][j]+tl[1]*r[1][j]+tl[2]*r[2][j]) > ra+eb[j] {
+			return false
+		}
+	}
+	// cross products of the axes: a[i] x b[j]
+	for i := 0; i < 3; i++ {
+		i1, i2 := (i+1)%3, (i+2)%3
+		for j := 0; j < 3; j++ {
+			j1, j2 := (j+1)%3, (j+2)%3
+			ra := ea[i1]*ar[i2][j] + ea[i2]*ar[i1][j]
+			rb := eb[j1]*ar[i][j2] + eb[j2]*ar[i][j1]
+			if math32.Abs(tl[i2]*r[i1][j]-tl[i1]*r[i2][j]) > ra+rb {
+				return false
+			}
+		}
+	}
+	return true
+}
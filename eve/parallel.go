@@ -0,0 +1,52 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package eve
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// parallelChunks calls fun on contiguous chunks [st, ed) of the indexes
+// from 0 to n-1, using up to workers goroutines.  fun must only write
+// to state that belongs to the indexes in its chunk, so that the results
+// do not depend on the number of workers or their scheduling, and are
+// identical to a serial run.  If workers <= 1, fun is called once with all
+// of the indexes, on the calling goroutine.
+func parallelChunks(n, workers int, fun func(st, ed int)) {
+	if workers <= 1 || n <= 1 {
+		if n > 0 {
+			fun(0, n)
+		}
+		return
+	}
+	nch := min(n, 4*workers)
+	var next atomic.Int32
+	var wg sync.WaitGroup
+	for range min(workers, nch) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				c := int(next.Add(1)) - 1
+				if c >= nch {
+					return
+				}
+				fun(c*n/nch, (c+1)*n/nch)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// parallelFor calls fun for each of the indexes from 0 to n-1, using up
+// to workers goroutines, subject to the same constraints as parallelChunks.
+func parallelFor(n, workers int, fun func(i int)) {
+	parallelChunks(n, workers, func(st, ed int) {
+		for i := st; i < ed; i++ {
+			fun(i)
+		}
+	})
+}
@@ -0,0 +1,165 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package eve
+
+import (
+	"fmt"
+	"testing"
+
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tree"
+)
+
+// makeParallelWorld makes a world with a static room, a scripted agent,
+// a kinematic turntable, and a pile of movable boxes and spheres, each
+// in its own top-level Dynamic group
+func makeParallelWorld() *Group {
+	w := newTestWorld()
+	st := NewGroup(w, "static")
+	newTestBox(st, "floor", math32.Vec3(0, -0.5, 0), math32.Vec3(20, 1, 20))
+	for i, x := range []float32{-5, 5} {
+		newTestBox(st, fmt.Sprintf("wall%d", i), math32.Vec3(x, 1, 0), math32.Vec3(1, 2, 10))
+	}
+
+	ag := NewGroup(w, "agent")
+	newTestBox(ag, "body", math32.Vec3(-3, 0.6, 0), math32.Vec3(0.5, 1, 0.5)).SetDynamic()
+	hd := NewSphere(ag, "head")
+	hd.Radius = 0.25
+	hd.SetInitPos(math32.Vec3(-3, 1.4, 0))
+	hd.SetDynamic()
+
+	tt := newTestBox(w, "turntable", math32.Vec3(2, 0.05, 0), math32.Vec3(3, 0.1, 3))
+	tt.SetKinematic()
+	tt.Rigid.Friction = 0.5
+
+	for i := range 24 {
+		gp := NewGroup(w, fmt.Sprintf("prop%d", i))
+		pos := math32.Vec3(float32(i%6)-2.5, 1+0.6*float32(i/6), float32(i%4)*0.3-0.5)
+		var bb *BodyBase
+		if i%2 == 0 {
+			bx := newTestBox(gp, fmt.Sprintf("box%d", i), pos, math32.Vec3(0.4, 0.4, 0.4))
+			bb = bx.AsBodyBase()
+		} else {
+			sp := NewSphere(gp, fmt.Sprintf("ball%d", i))
+			sp.Radius = 0.25
+			sp.SetInitPos(pos)
+			bb = sp.AsBodyBase()
+		}
+		bb.Rigid.SetMass(1)
+		bb.Rigid.Friction = 0.5
+		bb.Rigid.Bounce = 0.3
+		bb.SetInitLinVel(math32.Vec3(float32(i%3)-1, 0, float32(i%5)-2))
+		bb.SetDynamic()
+	}
+	w.WorldInit()
+	return w
+}
+
+// scriptParallelWorld applies the scripted motion for given step
+func scriptParallelWorld(w *Group, step int) {
+	ag := w.ChildByName("agent").(*Group)
+	ag.Rel.Pos.Set(0.01*float32(step), 0, 0)
+	ag.Rel.Quat.SetFromAxisAngle(math32.Vec3(0, 1, 0), 0.005*float32(step))
+	tt := w.ChildByName("turntable").(*Box)
+	tt.Rel.Quat.SetFromAxisAngle(math32.Vec3(0, 1, 0), 0.02*float32(step))
+}
+
+// compareWorlds reports any difference in the Abs state of the nodes
+// of the two given worlds, which must have the same structure
+func compareWorlds(t *testing.T, label string, a, b *Group) {
+	t.Helper()
+	var bn []*NodeBase
+	b.WalkDown(func(k tree.Node) bool {
+		_, ni := AsNode(k)
+		bn = append(bn, ni)
+		return true
+	})
+	i := 0
+	a.WalkDown(func(k tree.Node) bool {
+		_, ni := AsNode(k)
+		if ni.Abs != bn[i].Abs {
+			t.Errorf("%s: %s: Abs differs:\n%+v\n%+v", label, ni.Name(), ni.Abs, bn[i].Abs)
+		}
+		i++
+		return true
+	})
+}
+
+// compareContacts reports any difference between the two given lists of contacts
+func compareContacts(t *testing.T, label string, a, b []Contacts) {
+	t.Helper()
+	if len(a) != len(b) {
+		t.Errorf("%s: %d vs %d contact groups", label, len(a), len(b))
+		return
+	}
+	for i := range a {
+		if len(a[i]) != len(b[i]) {
+			t.Errorf("%s: group %d: %d vs %d contacts", label, i, len(a[i]), len(b[i]))
+			continue
+		}
+		for j, ca := range a[i] {
+			cb := b[i][j]
+			na := ca.A.AsNodeBase().Name() + "-" + ca.B.AsNodeBase().Name()
+			nb := cb.A.AsNodeBase().Name() + "-" + cb.B.AsNodeBase().Name()
+			if na != nb || ca.Dist != cb.Dist || ca.NormB != cb.NormB || ca.PtB != cb.PtB || ca.Offset != cb.Offset {
+				t.Errorf("%s: contact %d,%d differs: %s %+v vs %s %+v", label, i, j, na, *ca, nb, *cb)
+			}
+		}
+	}
+}
+
+func TestSolverParallel(t *testing.T) {
+	for _, flat := range []bool{false, true} {
+		ws := makeParallelWorld()
+		wp := makeParallelWorld()
+		ss := &Solver{}
+		ss.Defaults()
+		ss.Workers = 1
+		ss.Flat = flat
+		sp := &Solver{}
+		sp.Defaults()
+		sp.Workers = 8
+		sp.Flat = flat
+		ncts := 0
+		for step := range 200 {
+			scriptParallelWorld(ws, step)
+			scriptParallelWorld(wp, step)
+			cs := ss.Step(ws, 0.01)
+			cp := sp.Step(wp, 0.01)
+			label := fmt.Sprintf("flat: %v step: %d", flat, step)
+			compareContacts(t, label, cs, cp)
+			compareWorlds(t, label, ws, wp)
+			for _, cl := range cs {
+				ncts += len(cl)
+			}
+			if t.Failed() {
+				return
+			}
+		}
+		if ncts == 0 {
+			t.Errorf("flat: %v: no contacts were found", flat)
+		}
+	}
+}
+
+func TestWorldParallel(t *testing.T) {
+	ws := makeParallelWorld()
+	wp := makeParallelWorld()
+	for step := range 50 {
+		scriptParallelWorld(ws, step)
+		scriptParallelWorld(wp, step)
+		ws.WorldRelToAbs()
+		wp.WorldRelToAbsParallel(8)
+		label := fmt.Sprintf("step: %d", step)
+		compareWorlds(t, label+" RelToAbs", ws, wp)
+		compareContacts(t, label, ws.WorldCollide(DynsTopGps), wp.WorldCollideParallel(DynsTopGps, 8))
+		ws.WorldStepPhys(0.01)
+		wp.WorldStepPhysParallel(0.01, 8)
+		compareWorlds(t, label+" StepPhys", ws, wp)
+		if t.Failed() {
+			return
+		}
+	}
+}
@@ -0,0 +1,443 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package eve
+
+import (
+	"math/rand"
+
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tree"
+)
+
+// Particles is a set of many small spheres (e.g., sand, falling food
+// pellets, or the puffs of an odor or smoke plume), which are stored and
+// updated in bulk, rather than as individual Body nodes in the tree.
+// Each particle moves under gravity (scaled by GravityScale, e.g., negative
+// for rising smoke), is slowed by Drag, bounces off of and slides along the
+// static (non-Dynamic) bodies in the world if Collide is set, and is removed
+// when it has reached the end of its life.  If Granular is set, particles
+// also collide with each other, so that they pile up (e.g., sand).
+// Particles are added by Emit, or by the Emitters, and are updated by
+// Step, which the Solver calls for all Particles in the world.
+// The positions of the particles are in world coordinates, and all
+// particles are removed by InitAbs.
+type Particles struct {
+	NodeBase
+
+	// radius of each particle
+	Radius float32 `default:"0.02"`
+
+	// maximum number of particles: no more are emitted while there are this many
+	Max int `default:"1000"`
+
+	// multiplier on gravity for the particles, e.g., 0 for particles that drift, or negative for particles that rise
+	GravityScale float32 `default:"1"`
+
+	// proportion of the velocity of the particles that is lost per unit time, e.g., from air resistance
+	Drag float32 `default:"0.1"`
+
+	// whether particles collide with the static bodies in the world
+	Collide bool `default:"true"`
+
+	// whether particles collide with each other, so that they pile up
+	Granular bool
+
+	// coefficient of restitution for collisions: 0 = no bounce, 1 = full bounce
+	Bounce float32 `default:"0.2"`
+
+	// coefficient of friction for collisions
+	Friction float32 `default:"0.5"`
+
+	// number of iterations over all the collisions per step -- more iterations give more accurate results for piles of Granular particles
+	Iters int `default:"2"`
+
+	// sources that emit particles on each Step
+	Emitters []*Emitter
+
+	// seed for the random numbers used by the Emitters, for reproducible results
+	Seed int64
+
+	// color to draw in, as a standard color name or hex value
+	Color string
+
+	// current positions of the particles in world coordinates
+	Pos []math32.Vector3 `set:"-" edit:"-"`
+
+	// current velocities of the particles in world coordinates
+	Vel []math32.Vector3 `set:"-" edit:"-"`
+
+	// time since each particle was emitted
+	Age []float32 `set:"-" edit:"-"`
+
+	// lifetime of each particle, after which it is removed: 0 = forever
+	Life []float32 `set:"-" edit:"-"`
+
+	// random number generator for the Emitters
+	rand *rand.Rand
+}
+
+// Emitter is a source of Particles, which emits them at a given Rate
+// from a region around a point, in a cone of directions
+type Emitter struct {
+
+	// whether the emitter is emitting particles
+	On bool
+
+	// body that the emitter is attached to (e.g., the source of an odor), or nil for a fixed position in the world
+	Body Body
+
+	// position of the emitter, in the local coordinates of the Body, or in world coordinates if the Body is nil
+	Pos math32.Vector3
+
+	// half-size of the box around Pos in world coordinates that particles are emitted from, e.g., to pour sand from an area
+	Extent math32.Vector3
+
+	// direction in world coordinates that particles are emitted in
+	Dir math32.Vector3
+
+	// speed that particles are emitted with
+	Speed float32
+
+	// angle in degrees of the cone of directions around Dir that particles are emitted in
+	Spread float32
+
+	// number of particles emitted per unit time
+	Rate float32
+
+	// lifetime of the emitted particles: 0 = forever
+	Life float32
+
+	// fractional number of particles that remain to be emitted
+	accum float32
+}
+
+// NewEmitter returns a new Emitter that emits given number of particles
+// per unit time from given position in world coordinates,
+// with given lifetime (0 = forever), which is On
+func NewEmitter(pos math32.Vector3, rate, life float32) *Emitter {
+	return &Emitter{On: true, Pos: pos, Dir: math32.Vec3(0, 1, 0), Rate: rate, Life: life}
+}
+
+// Origin returns the current position of the emitter in world coordinates
+func (em *Emitter) Origin() math32.Vector3 {
+	if em.Body == nil {
+		return em.Pos
+	}
+	nb := em.Body.AsNodeBase()
+	return em.Pos.Mul(nb.Abs.ScaleFactor()).MulQuat(nb.Abs.Quat).Add(nb.Abs.Pos)
+}
+
+func (ps *Particles) EveNodeType() NodeTypes {
+	return PARTICLES
+}
+
+func (ps *Particles) Defaults() {
+	ps.Radius = 0.02
+	ps.Max = 1000
+	ps.GravityScale = 1
+	ps.Drag = 0.1
+	ps.Collide = true
+	ps.Bounce = 0.2
+	ps.Friction = 0.5
+	ps.Iters = 2
+}
+
+func (ps *Particles) OnInit() {
+	ps.Defaults()
+	ps.SetFlag(true, Dynamic)
+}
+
+func (ps *Particles) GroupBBox() {
+}
+
+// Len returns the current number of particles
+func (ps *Particles) Len() int {
+	return len(ps.Pos)
+}
+
+// Emit adds a particle at given position with given velocity in world
+// coordinates, and given lifetime (0 = forever).
+// Returns false if there are already Max particles.
+func (ps *Particles) Emit(pos, vel math32.Vector3, life float32) bool {
+	if len(ps.Pos) >= ps.Max {
+		return false
+	}
+	ps.Pos = append(ps.Pos, pos)
+	ps.Vel = append(ps.Vel, vel)
+	ps.Age = append(ps.Age, 0)
+	ps.Life = append(ps.Life, life)
+	return true
+}
+
+// Clear removes all the particles
+func (ps *Particles) Clear() {
+	ps.Pos = ps.Pos[:0]
+	ps.Vel = ps.Vel[:0]
+	ps.Age = ps.Age[:0]
+	ps.Life = ps.Life[:0]
+}
+
+func (ps *Particles) InitAbs(par *NodeBase) {
+	ps.InitAbsBase(par)
+	ps.Clear()
+	ps.rand = rand.New(rand.NewSource(ps.Seed))
+	for _, em := range ps.Emitters {
+		em.accum = 0
+	}
+	ps.SetBBox()
+}
+
+func (ps *Particles) RelToAbs(par *NodeBase) {
+	ps.RelToAbsBase(par)
+}
+
+// StepPhys does nothing, as Particles are updated by Step
+func (ps *Particles) StepPhys(step float32) {
+}
+
+// SetBBox sets the bounding box around the current particles
+func (ps *Particles) SetBBox() {
+	if len(ps.Pos) == 0 {
+		ps.BBox.SetBounds(ps.Abs.Pos, ps.Abs.Pos)
+	} else {
+		bb := math32.B3Empty()
+		bb.ExpandByPoints(ps.Pos)
+		r := math32.Vector3Scalar(ps.Radius)
+		ps.BBox.SetBounds(bb.Min.Sub(r), bb.Max.Add(r))
+	}
+	ps.BBox.OBB.Set(ps.BBox.BBox, math32.NewQuat(0, 0, 0, 1), math32.Vector3{})
+	ps.BBox.VelNilProject()
+}
+
+// Step updates the particles in given world by one step, with given gravity:
+// the Emitters emit new particles, the particles that have reached the end
+// of their life are removed, and the rest are moved and collided.
+// Collisions are resolved using position-based dynamics: overlaps are
+// corrected by directly moving the particles, along with friction over
+// the surface of contact, and velocities are computed from the resulting
+// motion, plus any Bounce.
+func (ps *Particles) Step(world *Group, step float32, gravity math32.Vector3) {
+	ps.emit(step)
+	ps.expire(step)
+	n := len(ps.Pos)
+	if n == 0 || step <= 0 {
+		ps.SetBBox()
+		return
+	}
+	dv := gravity.MulScalar(ps.GravityScale * step)
+	damp := max(1-ps.Drag*step, 0)
+	prev := make([]math32.Vector3, n)
+	copy(prev, ps.Pos)
+	bb := math32.B3Empty()
+	bb.ExpandByPoints(prev)
+	for i := range ps.Pos {
+		ps.Vel[i] = ps.Vel[i].Add(dv).MulScalar(damp)
+		ps.Pos[i].SetAdd(ps.Vel[i].MulScalar(step))
+	}
+	bb.ExpandByPoints(ps.Pos)
+	var obs []Body
+	if ps.Collide {
+		r := math32.Vector3Scalar(2 * ps.Radius)
+		bb.Min.SetSub(r)
+		bb.Max.SetAdd(r)
+		for _, ob := range world.BodiesInBox(bb, ps) {
+			if !ob.AsNodeBase().IsDynamic() {
+				obs = append(obs, ob)
+			}
+		}
+	}
+	contact := make([]math32.Vector3, n) // normal of the last body contact
+	for iter := 0; iter < max(ps.Iters, 1); iter++ {
+		if ps.Granular {
+			ps.collideParticles(prev)
+		}
+		if len(obs) > 0 {
+			ps.collideBodies(obs, prev, contact)
+		}
+	}
+	rest := 2 * dv.Length() // approach speed of resting contacts, which do not bounce
+	for i := range ps.Pos {
+		v0 := ps.Vel[i]
+		ps.Vel[i] = ps.Pos[i].Sub(prev[i]).DivScalar(step)
+		nrm := contact[i]
+		if nrm == (math32.Vector3{}) || ps.Bounce == 0 {
+			continue
+		}
+		if vn := v0.Dot(nrm); vn < -rest {
+			if dvn := -vn*ps.Bounce - ps.Vel[i].Dot(nrm); dvn > 0 {
+				ps.Vel[i].SetAdd(nrm.MulScalar(dvn))
+			}
+		}
+	}
+	ps.SetBBox()
+}
+
+// emit emits new particles from the Emitters for given time step
+func (ps *Particles) emit(step float32) {
+	if ps.rand == nil {
+		ps.rand = rand.New(rand.NewSource(ps.Seed))
+	}
+	for _, em := range ps.Emitters {
+		if !em.On {
+			continue
+		}
+		em.accum += em.Rate * step
+		org := em.Origin()
+		dir := em.Dir.Normal()
+		for ; em.accum >= 1; em.accum-- {
+			pos := org.Add(math32.Vec3(ps.randRange(em.Extent.X), ps.randRange(em.Extent.Y), ps.randRange(em.Extent.Z)))
+			ps.Emit(pos, ps.randCone(dir, em.Spread).MulScalar(em.Speed), em.Life)
+		}
+	}
+}
+
+// randRange returns a uniform random number between -r and r
+func (ps *Particles) randRange(r float32) float32 {
+	if r == 0 {
+		return 0
+	}
+	return r * (2*ps.rand.Float32() - 1)
+}
+
+// randCone returns a uniform random unit vector within given angle
+// in degrees of given unit direction
+func (ps *Particles) randCone(dir math32.Vector3, angle float32) math32.Vector3 {
+	if angle <= 0 || dir == (math32.Vector3{}) {
+		return dir
+	}
+	cmin := math32.Cos(math32.DegToRad(min(angle, 180)))
+	z := cmin + (1-cmin)*ps.rand.Float32()
+	phi := 2 * math32.Pi * ps.rand.Float32()
+	s := math32.Sqrt(max(1-z*z, 0))
+	t1 := tangentTo(dir)
+	t2 := dir.Cross(t1)
+	return dir.MulScalar(z).Add(t1.MulScalar(s * math32.Cos(phi))).Add(t2.MulScalar(s * math32.Sin(phi)))
+}
+
+// expire ages the particles by given time step, and removes those that
+// have reached the end of their life, preserving the order of the rest
+func (ps *Particles) expire(step float32) {
+	j := 0
+	for i := range ps.Pos {
+		ps.Age[i] += step
+		if ps.Life[i] > 0 && ps.Age[i] >= ps.Life[i] {
+			continue
+		}
+		ps.Pos[j], ps.Vel[j], ps.Age[j], ps.Life[j] = ps.Pos[i], ps.Vel[i], ps.Age[i], ps.Life[i]
+		j++
+	}
+	ps.Pos, ps.Vel, ps.Age, ps.Life = ps.Pos[:j], ps.Vel[:j], ps.Age[:j], ps.Life[:j]
+}
+
+// friction reduces the tangential part of given motion relative to a
+// surface with given normal, by Friction times given depth of the overlap
+// that was corrected along the normal, returning the reduction
+func (ps *Particles) friction(mv, n math32.Vector3, depth float32) math32.Vector3 {
+	tan := mv.Sub(n.MulScalar(mv.Dot(n)))
+	tl := tan.Length()
+	if tl == 0 {
+		return tan
+	}
+	return tan.MulScalar(min(ps.Friction*depth/tl, 1))
+}
+
+// collideBodies moves all the particles out of given bodies, with friction
+// on their motion since given previous positions, recording the normal
+// of any contact in given slice
+func (ps *Particles) collideBodies(obs []Body, prev, contact []math32.Vector3) {
+	probe := &Sphere{Radius: ps.Radius}
+	r := math32.Vector3Scalar(ps.Radius)
+	poses := make([]shapePose, len(obs))
+	for oi, ob := range obs {
+		poses[oi] = bodyPose(ob)
+	}
+	for i := range ps.Pos {
+		for oi, ob := range obs {
+			pb := math32.Box3{Min: ps.Pos[i].Sub(r), Max: ps.Pos[i].Add(r)}
+			if !ob.AsNodeBase().BBox.BBox.IntersectsBox(pb) {
+				continue
+			}
+			sp := shapePose{bod: probe, pos: ps.Pos[i], quat: math32.NewQuat(0, 0, 0, 1), scale: math32.Vec3(1, 1, 1)}
+			d, _, _, n := shapeDist(&sp, &poses[oi])
+			if d >= 0 {
+				continue
+			}
+			ps.Pos[i].SetAdd(n.MulScalar(-d))
+			ps.Pos[i].SetSub(ps.friction(ps.Pos[i].Sub(prev[i]), n, -d))
+			contact[i] = n
+		}
+	}
+}
+
+// particleCell is the key of a cell in the grid used for collisions
+// between particles
+type particleCell struct {
+	X, Y, Z int32
+}
+
+// collideParticles separates overlapping particles, using a uniform grid
+// with cells the size of a particle, with friction on their relative
+// motion since given previous positions.
+func (ps *Particles) collideParticles(prev []math32.Vector3) {
+	dia := 2 * ps.Radius
+	if dia <= 0 {
+		return
+	}
+	cellOf := func(p math32.Vector3) particleCell {
+		return particleCell{int32(math32.Floor(p.X / dia)), int32(math32.Floor(p.Y / dia)), int32(math32.Floor(p.Z / dia))}
+	}
+	grid := make(map[particleCell][]int, len(ps.Pos))
+	for i, p := range ps.Pos {
+		c := cellOf(p)
+		grid[c] = append(grid[c], i)
+	}
+	for i := range ps.Pos {
+		c := cellOf(ps.Pos[i])
+		for dx := int32(-1); dx <= 1; dx++ {
+			for dy := int32(-1); dy <= 1; dy++ {
+				for dz := int32(-1); dz <= 1; dz++ {
+					for _, j := range grid[particleCell{c.X + dx, c.Y + dy, c.Z + dz}] {
+						if j <= i {
+							continue
+						}
+						d := ps.Pos[i].Sub(ps.Pos[j])
+						dl := d.Length()
+						if dl >= dia {
+							continue
+						}
+						n := math32.Vec3(0, 1, 0)
+						if dl > 0 {
+							n = d.DivScalar(dl)
+						}
+						depth := dia - dl
+						rel := ps.Pos[i].Sub(prev[i]).Sub(ps.Pos[j].Sub(prev[j]))
+						corr := n.MulScalar(depth).Sub(ps.friction(rel, n, depth)).MulScalar(0.5)
+						ps.Pos[i].SetAdd(corr)
+						ps.Pos[j].SetSub(corr)
+					}
+				}
+			}
+		}
+	}
+}
+
+// StepParticles updates all the Particles in the world by one step
+// (see Particles.Step), which is called by Step after StepMovable.
+func (sv *Solver) StepParticles(world *Group, step float32) {
+	world.WalkDown(func(k tree.Node) bool {
+		nii, _ := AsNode(k)
+		if nii == nil {
+			return false // going into a different type of thing, bail
+		}
+		if !nii.IsDynamic() {
+			return false
+		}
+		if ps, ok := k.(*Particles); ok {
+			ps.Step(world, step, sv.Gravity)
+			return false
+		}
+		return true
+	})
+}
@@ -21,6 +21,9 @@ type Phys struct {
 	// rotation specified as a Quat
 	Quat math32.Quat
 
+	// scale factors along the local X, Y, Z axes, which multiply the size of body shapes and the positions of child nodes -- zero is treated as 1 (unscaled).  Non-uniform scales of a parent are only represented exactly for children that are rotated by multiples of 90 degrees relative to it, as they would otherwise be sheared.
+	Scale math32.Vector3
+
 	// linear velocity
 	LinVel math32.Vector3
 
@@ -33,19 +36,62 @@ func (ps *Phys) Defaults() {
 	if ps.Quat.IsNil() {
 		ps.Quat.SetIdentity()
 	}
+	if ps.Scale == (math32.Vector3{}) {
+		ps.Scale.Set(1, 1, 1)
+	}
+}
+
+// ScaleFactor returns the Scale, or 1 for all axes if it is zero (not set)
+func (ps *Phys) ScaleFactor() math32.Vector3 {
+	if ps.Scale == (math32.Vector3{}) {
+		return math32.Vec3(1, 1, 1)
+	}
+	return ps.Scale
 }
 
 ///////////////////////////////////////////////////////
 // 	State updates
 
-// FromRel sets state from relative values compared to a parent state
+// FromRel sets state from relative values compared to a parent state.
+// The Scale is the product of the relative scale and the parent scale along
+// each of the relative axes, which is exact for uniform parent scales.
 func (ps *Phys) FromRel(rel, par *Phys) {
+	psc := par.ScaleFactor()
+	rsc := rel.ScaleFactor()
+	if psc.X == psc.Y && psc.Y == psc.Z {
+		ps.Scale = rsc.MulScalar(psc.X)
+	} else {
+		ps.Scale.X = rsc.X * math32.Vec3(1, 0, 0).MulQuat(rel.Quat).Mul(psc).Length()
+		ps.Scale.Y = rsc.Y * math32.Vec3(0, 1, 0).MulQuat(rel.Quat).Mul(psc).Length()
+		ps.Scale.Z = rsc.Z * math32.Vec3(0, 0, 1).MulQuat(rel.Quat).Mul(psc).Length()
+	}
 	ps.Quat = rel.Quat.Mul(par.Quat)
-	ps.Pos = rel.Pos.MulQuat(par.Quat).Add(par.Pos)
+	ps.Pos = rel.Pos.Mul(psc).MulQuat(par.Quat).Add(par.Pos)
 	ps.LinVel = rel.LinVel.MulQuat(rel.Quat).Add(par.LinVel)
 	ps.AngVel = rel.AngVel.MulQuat(rel.Quat).Add(par.AngVel)
 }
 
+// ToRel sets state to the relative values compared to a parent state
+// that result in the given absolute state, as the inverse of FromRel,
+// e.g., for the pose of one node in the frame of another.
+func (ps *Phys) ToRel(abs, par *Phys) {
+	psc := par.ScaleFactor()
+	asc := abs.ScaleFactor()
+	ipq := par.Quat.Conjugate()
+	ps.Quat = abs.Quat.Mul(ipq)
+	ps.Pos = abs.Pos.Sub(par.Pos).MulQuat(ipq).Div(psc)
+	if psc.X == psc.Y && psc.Y == psc.Z {
+		ps.Scale = asc.DivScalar(psc.X)
+	} else {
+		ps.Scale.X = asc.X / math32.Vec3(1, 0, 0).MulQuat(ps.Quat).Mul(psc).Length()
+		ps.Scale.Y = asc.Y / math32.Vec3(0, 1, 0).MulQuat(ps.Quat).Mul(psc).Length()
+		ps.Scale.Z = asc.Z / math32.Vec3(0, 0, 1).MulQuat(ps.Quat).Mul(psc).Length()
+	}
+	irq := ps.Quat.Conjugate()
+	ps.LinVel = abs.LinVel.Sub(par.LinVel).MulQuat(irq)
+	ps.AngVel = abs.AngVel.Sub(par.AngVel).MulQuat(irq)
+}
+
 // AngMotionMax is maximum angular motion that can be taken per update
 const AngMotionMax = math.Pi / 4
 
@@ -65,12 +111,29 @@ func (ps *Phys) StepByAngVel(step float32) {
 		// sync(fAngle) = sin(c*fAngle)/t
 		axis = ps.AngVel.MulScalar(math32.Sin(0.5*ang*step) / ang)
 	}
-	var dq math32.Quat
-	dq.SetFromAxisAngle(axis, ang*step)
+	dq := math32.NewQuat(axis.X, axis.Y, axis.Z, math32.Cos(0.5*ang*step))
 	ps.Quat = dq.Mul(ps.Quat)
 	ps.Quat.Normalize()
 }
 
+// QuatDelta returns the rotation from one rotation to another,
+// as a rotation vector (axis scaled by angle in radians), which is
+// the angular velocity that moves from one to the other in a unit
+// of time, as used in StepByAngVel.
+func QuatDelta(from, to math32.Quat) math32.Vector3 {
+	dq := to.Mul(from.Conjugate())
+	if dq.W < 0 { // shortest path
+		dq.Set(-dq.X, -dq.Y, -dq.Z, -dq.W)
+	}
+	v := math32.Vec3(dq.X, dq.Y, dq.Z)
+	s := v.Length()
+	if s < 1.0e-7 {
+		return v.MulScalar(2)
+	}
+	ang := 2 * math32.Atan2(s, dq.W)
+	return v.MulScalar(ang / s)
+}
+
 // StepByLinVel steps the Pos from the linear velocity
 func (ps *Phys) StepByLinVel(step float32) {
 	ps.Pos = ps.Pos.Add(ps.LinVel.MulScalar(step))
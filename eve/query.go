@@ -0,0 +1,275 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package eve
+
+import (
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tree"
+)
+
+// separationMargin is the extra distance that bodies are separated by,
+// so that they are not still overlapping due to numerical error
+const separationMargin = 1.0e-4
+
+// BodiesInBox returns the colliding bodies in the world (see Collides) whose
+// BBox intersects the given box in world coords, pruning groups whose BBox
+// does not intersect it, including any Shared static geometry.
+// The subtree at skip (e.g., an agent group or the body being queried)
+// is skipped, if non-nil.  This and the other queries based on it do not
+// wrap around the edges of a wrapped world (see Bounds).
+func (gp *Group) BodiesInBox(box math32.Box3, skip tree.Node) []Body {
+	var bods []Body
+	var skipThis tree.Node
+	if skip != nil {
+		skipThis = skip.This()
+	}
+	gp.walkShared(func(k tree.Node) bool {
+		nii, ni := AsNode(k)
+		if nii == nil {
+			return false // going into a different type of thing, bail
+		}
+		if skipThis != nil && k.This() == skipThis {
+			return false
+		}
+		if nii.EveNodeType() != BODY {
+			return ni.BBox.BBox.IsEmpty() || ni.BBox.BBox.IntersectsBox(box)
+		}
+		if bod := nii.AsBody(); Collides(bod) && ni.BBox.BBox.IntersectsBox(box) {
+			bods = append(bods, bod)
+		}
+		return false
+	})
+	return bods
+}
+
+// OverlapShape returns the colliding bodies in the world (see Collides)
+// whose collision shapes overlap the collision shape of given body, at
+// unit scale, when placed at given position and rotation in world coords.
+// The shape body does not need to be in the world, e.g., it can be a Box
+// that is made just for the query, as in OverlapBox.  The subtree at skip
+// (e.g., an agent group) is skipped, if non-nil.  This is useful for
+// checking that a spawn point is free, or which bodies are in a region.
+func (gp *Group) OverlapShape(shape Body, pos math32.Vector3, quat math32.Quat, skip tree.Node) []Body {
+	sp := shapePose{bod: shape, pos: pos, quat: quat, scale: math32.Vec3(1, 1, 1)}
+	var bods []Body
+	for _, ob := range gp.BodiesInBox(shapeBox(&sp), skip) {
+		op := bodyPose(ob)
+		if d, _, _, _ := shapeDist(&sp, &op); d < 0 {
+			bods = append(bods, ob)
+		}
+	}
+	return bods
+}
+
+// OverlapSphere returns the colliding bodies in the world whose collision
+// shapes overlap a sphere with given center and radius (see OverlapShape)
+func (gp *Group) OverlapSphere(center math32.Vector3, radius float32, skip tree.Node) []Body {
+	return gp.OverlapShape(&Sphere{Radius: radius}, center, math32.NewQuat(0, 0, 0, 1), skip)
+}
+
+// OverlapBox returns the colliding bodies in the world whose collision
+// shapes overlap a box with given center, size and rotation (see OverlapShape)
+func (gp *Group) OverlapBox(center, size math32.Vector3, quat math32.Quat, skip tree.Node) []Body {
+	return gp.OverlapShape(&Box{Size: size}, center, quat, skip)
+}
+
+// OverlapCapsule returns the colliding bodies in the world whose collision
+// shapes overlap a capsule with given center, height of the cylinder
+// between the end caps, radius, and rotation, with its axis along Y
+// before rotation (see OverlapShape)
+func (gp *Group) OverlapCapsule(center math32.Vector3, height, radius float32, quat math32.Quat, skip tree.Node) []Body {
+	return gp.OverlapShape(&Capsule{Height: height, TopRad: radius, BotRad: radius}, center, quat, skip)
+}
+
+// ClosestPoint returns the point on the surface of the collision shape of
+// given body that is closest to given point in world coords, and the
+// signed distance from the surface to the point: positive outside of the
+// shape, and negative inside, by the depth below the surface.
+// See ShapeDist for the distance and closest points between two bodies.
+func ClosestPoint(bod Body, pt math32.Vector3) (math32.Vector3, float32) {
+	pp := pointPose(pt)
+	bp := bodyPose(bod)
+	d, _, ptB, _ := shapeDist(&pp, &bp)
+	return ptB, d
+}
+
+// NearestBody returns the colliding body in the world whose collision
+// shape is nearest to given point in world coords, within maxDist,
+// along with the closest point on it and the signed distance to it
+// (see ClosestPoint), or nil if there is none, e.g., for a sensor of
+// the nearest object.  The subtree at skip (e.g., an agent group) is
+// skipped, if non-nil.
+func (gp *Group) NearestBody(pt math32.Vector3, maxDist float32, skip tree.Node) (Body, math32.Vector3, float32) {
+	ext := math32.Vec3(maxDist, maxDist, maxDist)
+	var near Body
+	var npt math32.Vector3
+	ndist := maxDist
+	for _, ob := range gp.BodiesInBox(math32.Box3{Min: pt.Sub(ext), Max: pt.Add(ext)}, skip) {
+		cp, d := ClosestPoint(ob, pt)
+		if d <= ndist {
+			near, npt, ndist = ob, cp, d
+		}
+	}
+	if near == nil {
+		return nil, math32.Vector3{}, 0
+	}
+	return near, npt, ndist
+}
+
+// SeparationVector returns the minimal translation in world coords that
+// separates the given body from all the static (non-Dynamic) bodies in the
+// world, and true if the body is then free of all overlaps.  The translation
+// is zero if the body does not overlap any static geometry.  It is computed
+// by iteratively pushing the body out of each overlapping body along the
+// direction of least penetration, which is exact for a single overlap.
+func (gp *Group) SeparationVector(bod Body) (math32.Vector3, bool) {
+	bb := bod.AsNodeBase().BBox.BBox
+	var obs []Body
+	for _, ob := range gp.BodiesInBox(bb, bod) {
+		if !ob.AsNodeBase().IsDynamic() {
+			obs = append(obs, ob)
+		}
+	}
+	return separation(bodyPose(bod), obs, separationMargin, 8)
+}
+
+// WorldDepenetrate moves the object containing given body by its
+// SeparationVector, so that it no longer overlaps any static geometry,
+// e.g., after it has been placed at a random position.  The object that is
+// moved is the body itself if it is movable (see IsMovable), and otherwise
+// the scripted object that contains it (e.g., an agent), as determined by
+// SpatialHash, or the body itself if it is not within one.
+// Returns true if the body is then free of all overlaps.
+func (gp *Group) WorldDepenetrate(bod Body) bool {
+	sep, ok := gp.SeparationVector(bod)
+	if sep != (math32.Vector3{}) {
+		gp.objectRoot(bod).AsNodeBase().shiftAbs(sep)
+		gp.WorldDynGroupBBox()
+	}
+	return ok
+}
+
+// WorldResolveOverlaps resolves all current overlaps between Dynamic bodies
+// and the rest of the world, as found by a SpatialHash, by moving the
+// objects containing the Dynamic bodies (see WorldDepenetrate), over up to
+// given number of iterations.  Objects are first moved out of static bodies,
+// and overlaps between Dynamic bodies are then resolved by moving both
+// objects half of the way, or only one of them if the other would be
+// pushed back into a static body that it was moved out of.
+// Velocities are not affected.  Returns the number of overlaps remaining.
+func (gp *Group) WorldResolveOverlaps(iters int) int {
+	var sh SpatialHash
+	walls := make(map[Node][]math32.Vector3) // normals of static overlaps
+	blocked := func(r Node, dir math32.Vector3) bool {
+		for _, n := range walls[r] {
+			if n.Dot(dir) < 0 {
+				return true
+			}
+		}
+		return false
+	}
+	for iter := 0; ; iter++ {
+		var dyn []*Contact
+		nover := 0
+		for _, cl := range sh.Collide(gp) {
+			for _, c := range cl {
+				if c.B.AsNodeBase().IsDynamic() {
+					dyn = append(dyn, c)
+					continue
+				}
+				d, _, _, n := ShapeDist(c.A, c.B)
+				if d >= 0 {
+					continue
+				}
+				nover++
+				if iter < iters {
+					ra := gp.objectRoot(c.A)
+					ra.AsNodeBase().shiftAbs(n.MulScalar(-d + separationMargin))
+					walls[ra] = append(walls[ra], n)
+				}
+			}
+		}
+		for _, c := range dyn {
+			ra, rb := gp.objectRoot(c.A), gp.objectRoot(c.B)
+			if ra == rb {
+				continue
+			}
+			d, _, _, n := ShapeDist(c.A, c.B)
+			if d >= 0 {
+				continue
+			}
+			nover++
+			if iter == iters {
+				continue
+			}
+			push := n.MulScalar(-d + separationMargin)
+			ba, bb := blocked(ra, push), blocked(rb, push.Negate())
+			switch {
+			case ba && !bb:
+				rb.AsNodeBase().shiftAbs(push.Negate())
+			case bb && !ba:
+				ra.AsNodeBase().shiftAbs(push)
+			default:
+				half := push.MulScalar(.5)
+				ra.AsNodeBase().shiftAbs(half)
+				rb.AsNodeBase().shiftAbs(half.Negate())
+			}
+		}
+		if nover == 0 || iter == iters {
+			return nover
+		}
+		gp.WorldDynGroupBBox()
+	}
+}
+
+// objectRoot returns the node that is moved to move given body as an
+// object: the body itself if it is movable or static, and otherwise the
+// highest group under this world that is a scripted object containing it
+// (see scriptedGroup), if any.
+func (gp *Group) objectRoot(bod Body) Node {
+	if IsMovable(bod) || !bod.AsNodeBase().IsDynamic() {
+		return bod
+	}
+	var root Node = bod
+	scripted := make(map[tree.Node]bool)
+	for p := bod.Parent(); p != nil && p != tree.Node(gp); p = p.Parent() {
+		if !scriptedGroup(p, scripted) {
+			break
+		}
+		if pn, _ := AsNode(p); pn != nil {
+			root = pn
+		}
+	}
+	return root
+}
+
+// separation returns the translation that separates the shape at given
+// pose from all of the given obstacles, by given margin, computed by
+// iteratively pushing it out of each overlapping obstacle along the
+// penetration normal, up to given number of iterations,
+// and true if no overlaps remain.
+func separation(sp shapePose, obs []Body, margin float32, iters int) (math32.Vector3, bool) {
+	var off math32.Vector3
+	base := sp.pos
+	for iter := 0; iter <= iters; iter++ {
+		moved := false
+		for _, ob := range obs {
+			sp.pos = base.Add(off)
+			op := bodyPose(ob)
+			d, _, _, n := shapeDist(&sp, &op)
+			if d < 0 {
+				if iter == iters {
+					return off, false
+				}
+				off.SetAdd(n.MulScalar(-d + margin))
+				moved = true
+			}
+		}
+		if !moved {
+			break
+		}
+	}
+	return off, true
+}
@@ -12,7 +12,7 @@ import (
 // properties including position, orientation, velocity.  These
 type Rigid struct {
 
-	// 1/mass -- 0 for no mass
+	// 1/mass -- 0 for no mass -- this is the mass of the unscaled shape, which scales with its volume (see BodyBase.ScaledMass)
 	InvMass float32
 
 	// COR or coefficient of restitution -- how elastic is the collision i.e., final velocity / initial velocity
@@ -21,13 +21,110 @@ type Rigid struct {
 	// friction coefficient -- how much friction is generated by transverse motion
 	Friction float32
 
+	// static friction coefficient -- how much transverse force can be resisted without sliding -- if 0, Friction is used
+	StaticFriction float32
+
+	// rolling friction coefficient, in units of length -- resists rolling, mainly relevant for spheres and cylinders
+	RollFriction float32
+
+	// spinning friction coefficient, in units of length -- resists spinning about the contact normal
+	SpinFriction float32
+
+	// name of the surface material, which is looked up in the Materials table of the Solver to determine the surface properties -- if empty or not found, the Friction, Bounce etc values here are used
+	Material string
+
+	// direction in local body coordinates for anisotropic friction (e.g., the blade of a skate or the rolling direction of a wheel) -- if zero, friction is isotropic
+	FrictionDir math32.Vector3
+
+	// factor multiplying friction along FrictionDir, e.g., a small value for a skate blade that slides easily forward but not sideways -- if 0, 1 is used
+	FrictionDirScale float32
+
+	// velocity of the surface of the body relative to the body itself, in local body coordinates, e.g., the belt of a conveyor, which carries bodies that rest on it along by friction, while the body itself stays put
+	SurfaceVel math32.Vector3
+
+	// per-axis locking of linear motion along the X, Y, Z world axes in the Solver: 0 = free, 1 = fully locked, with intermediate values scaling the response to impulses and gravity
+	LinLock math32.Vector3
+
+	// per-axis locking of angular motion (rotation about the X, Y, Z world axes) in the Solver: 0 = free, 1 = fully locked, with intermediate values scaling the response to impulses
+	AngLock math32.Vector3
+
 	// record of computed force vector from last iteration
 	Force math32.Vector3
 
-	// Last calculated rotational inertia matrix in local coords
+	// rotational inertia matrix in local coords -- if left at zero, it is computed from the shape and mass of the body, otherwise the value set here is used as-is
 	RotInertia math32.Matrix3
+
+	// true if RotInertia was computed from the shape, so it is kept up to date with changes in shape and mass
+	autoInertia bool
 }
 
 // Defaults sets defaults only if current values are nil
 func (ps *Rigid) Defaults() {
 }
+
+// DirScale returns the FrictionDirScale, or 1 if that is 0
+func (ps *Rigid) DirScale() float32 {
+	if ps.FrictionDirScale == 0 {
+		return 1
+	}
+	return ps.FrictionDirScale
+}
+
+// Surface returns the surface properties of this body
+func (ps *Rigid) Surface() Surface {
+	return Surface{Friction: ps.Friction, StaticFriction: ps.StaticFriction, Bounce: ps.Bounce, RollFriction: ps.RollFriction, SpinFriction: ps.SpinFriction}
+}
+
+// Mass returns the mass from InvMass -- 0 if InvMass is 0
+func (ps *Rigid) Mass() float32 {
+	if ps.InvMass == 0 {
+		return 0
+	}
+	return 1 / ps.InvMass
+}
+
+// SetMass sets InvMass from given mass -- 0 = no mass
+func (ps *Rigid) SetMass(mass float32) {
+	if mass == 0 {
+		ps.InvMass = 0
+		return
+	}
+	ps.InvMass = 1 / mass
+}
+
+// LockFactors returns the factors that multiply linear and angular
+// motion along each axis, from LinLock and AngLock: 1 = free, 0 = locked.
+func (ps *Rigid) LockFactors() (lin, ang math32.Vector3) {
+	one := math32.Vector3Scalar(1)
+	return one.Sub(ps.LinLock), one.Sub(ps.AngLock)
+}
+
+// SetRotInertiaDiag sets the RotInertia to a diagonal matrix with given
+// principal moments of inertia, which is the case for all the basic shapes.
+func (ps *Rigid) SetRotInertiaDiag(diag math32.Vector3) {
+	ps.RotInertia.Set(diag.X, 0, 0, 0, diag.Y, 0, 0, 0, diag.Z)
+}
+
+// SetShapeInertia sets the RotInertia to a diagonal matrix with given
+// principal moments of inertia computed from the shape of the body,
+// unless RotInertia has been set directly by the user.
+func (ps *Rigid) SetShapeInertia(diag math32.Vector3) {
+	if !ps.autoInertia && ps.RotInertia != (math32.Matrix3{}) {
+		return
+	}
+	ps.SetRotInertiaDiag(diag)
+	ps.autoInertia = true
+}
+
+// InvRotInertia returns the inverse of the RotInertia matrix
+// rotated into world coordinates by given rotation.
+// Returns a zero matrix if the inertia is not invertible (e.g., no mass).
+func (ps *Rigid) InvRotInertia(q math32.Quat) math32.Matrix3 {
+	inv, err := ps.RotInertia.InverseTry()
+	if err != nil {
+		return math32.Matrix3{}
+	}
+	var rot math32.Matrix3
+	rot.SetRotationFromQuat(q)
+	return rot.Mul(inv).Mul(rot.Transpose())
+}
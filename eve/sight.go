@@ -0,0 +1,173 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package eve
+
+import (
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tree"
+)
+
+// sightSkin is the distance from a surface at which a line of sight
+// is considered to hit it
+const sightSkin = 1.0e-4
+
+// Sight determines the ground-truth visibility of bodies from the viewpoint
+// of an Eye node, e.g., for attention, social gaze or hide-and-seek tasks,
+// without rendering an image.  The Eye looks along its -Z axis with Y up,
+// as for a camera (see evev.View.RenderOffNode), within a field of view and
+// maximum distance, and lines of sight are blocked by the collision shapes
+// of the occluding bodies.  Visible samples lines of sight to points
+// spread over the outline of the target body as seen from the Eye,
+// and returns the fraction of them that reach it.
+type Sight struct {
+
+	// node whose Abs pose is the viewpoint, typically an eye body or group of an agent
+	Eye Node
+
+	// horizontal field of view in degrees -- 0 or 360 sees all around
+	FOV float32 `default:"90"`
+
+	// vertical field of view in degrees -- 0 uses FOV
+	VFOV float32
+
+	// maximum distance from the Eye to a visible point
+	MaxDist float32 `default:"20"`
+
+	// number of lines of sight to points on the target: the first is to its center, and the rest are spread over its outline as seen from the Eye
+	Samples int `default:"16"`
+
+	// subtree of the world whose bodies do not block the view (e.g., the agent that the Eye belongs to)
+	Skip tree.Node
+
+	// if non-nil, only these bodies block the view -- otherwise all colliding bodies in the world do (see Collides), except for those in Skip
+	Occluders []Body
+}
+
+// NewSight returns a new Sight for given eye node, with default parameters
+func NewSight(eye Node) *Sight {
+	st := &Sight{Eye: eye}
+	st.Defaults()
+	return st
+}
+
+func (st *Sight) Defaults() {
+	st.FOV = 90
+	st.MaxDist = 20
+	st.Samples = 16
+}
+
+// InView returns true if given point in world coords is within the field
+// of view and maximum distance of the Eye, regardless of any occluders
+func (st *Sight) InView(pt math32.Vector3) bool {
+	eb := st.Eye.AsNodeBase()
+	d := pt.Sub(eb.Abs.Pos)
+	dist := d.Length()
+	if dist > st.MaxDist {
+		return false
+	}
+	if dist == 0 || st.FOV <= 0 || st.FOV >= 360 {
+		return true
+	}
+	ld := eb.LocalDir(d)
+	hang := math32.RadToDeg(math32.Atan2(ld.X, -ld.Z))
+	vang := math32.RadToDeg(math32.Atan2(ld.Y, math32.Sqrt(ld.X*ld.X+ld.Z*ld.Z)))
+	vfov := st.VFOV
+	if vfov <= 0 {
+		vfov = st.FOV
+	}
+	return math32.Abs(hang) <= 0.5*st.FOV && math32.Abs(vang) <= 0.5*vfov
+}
+
+// Visible returns whether the target body can be seen from the Eye, in
+// the given world, and the fraction of the lines of sight to sample
+// points over its outline that reach it within the field of view and
+// maximum distance, without being blocked by an occluder.
+func (st *Sight) Visible(world *Group, target Body) (bool, float32) {
+	eye := st.Eye.AsNodeBase().Abs.Pos
+	tp := bodyPose(target)
+	view := tp.pos.Sub(eye)
+	if view.LengthSquared() == 0 {
+		return true, 1
+	}
+	vd := view.Normal()
+	u := tangentTo(vd)
+	v := vd.Cross(u)
+	ns := max(st.Samples, 1)
+	nhit, nvis := 0, 0
+	for i := range ns {
+		pt := tp.pos
+		if i > 0 {
+			// spread over the outline on a sunflower spiral
+			ang := float32(i) * math32.Pi * (3 - math32.Sqrt(5))
+			dir := u.MulScalar(math32.Cos(ang)).Add(v.MulScalar(math32.Sin(ang)))
+			ext := tp.support(dir).Sub(tp.pos).Dot(dir)
+			pt.SetAdd(dir.MulScalar(0.95 * ext * math32.Sqrt(float32(i)/float32(ns))))
+		}
+		end, ok := st.sightTo(&tp, eye, pt)
+		if !ok {
+			continue // misses the target at its edge
+		}
+		nhit++
+		if st.InView(end) && !st.blocked(world, target, eye, end) {
+			nvis++
+		}
+	}
+	if nhit == 0 {
+		return false, 0
+	}
+	frac := float32(nvis) / float32(nhit)
+	return nvis > 0, frac
+}
+
+// sightTo returns the point at which the line of sight from eye through
+// given point first reaches the target shape, and false if it misses it
+func (st *Sight) sightTo(tp *shapePose, eye, pt math32.Vector3) (math32.Vector3, bool) {
+	motion := pt.Sub(eye).MulScalar(2)
+	ep := pointPose(eye)
+	t, _, ok := shapeCast(&ep, motion, tp, sightSkin)
+	if !ok {
+		if d, _, _, _ := shapeDist(&ep, tp); d <= sightSkin {
+			return eye, true // eye is at or within the target
+		}
+		return math32.Vector3{}, false
+	}
+	return eye.Add(motion.MulScalar(t)), true
+}
+
+// blocked returns true if the line of sight from eye to given end point
+// on the target is blocked by an occluder
+func (st *Sight) blocked(world *Group, target Body, eye, end math32.Vector3) bool {
+	obs := st.Occluders
+	if obs == nil {
+		box := math32.B3Empty()
+		box.ExpandByPoint(eye)
+		box.ExpandByPoint(end)
+		obs = world.BodiesInBox(box, st.Skip)
+	}
+	ep := pointPose(eye)
+	motion := end.Sub(eye)
+	for _, ob := range obs {
+		if ob == target {
+			continue
+		}
+		op := bodyPose(ob)
+		if t, _, ok := shapeCast(&ep, motion, &op, sightSkin); ok && t < 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// CanSee returns true if the target body can be seen from the Eye, along
+// the single line of sight to its center, which is faster than Visible
+func (st *Sight) CanSee(world *Group, target Body) bool {
+	tp := bodyPose(target)
+	eye := st.Eye.AsNodeBase().Abs.Pos
+	end, ok := st.sightTo(&tp, eye, tp.pos)
+	if !ok {
+		end = tp.pos
+	}
+	return st.InView(end) && !st.blocked(world, target, eye, end)
+}
@@ -0,0 +1,460 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package eve
+
+import (
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tree"
+)
+
+// Soft is a deformable body (e.g., a rope, string, curtain or flexible
+// barrier), made of points connected by distance constraints (Links),
+// which is simulated by the Solver using position-based dynamics:
+// the points move under gravity, the constraints are enforced by directly
+// correcting their positions, and they are pushed out of the collision
+// shapes of the bodies in the world.  Points can be attached to bodies
+// with Pins, which pull on movable bodies in turn (e.g., pulling a string
+// that is tied to an object).  The current positions of the points are in
+// world coordinates (Pos), and are initialized by InitAbs from the Points
+// in local coordinates: changing the Rel pose afterwards has no effect.
+type Soft struct {
+	NodeBase
+
+	// positions of the points in local coordinates relative to this node, which determine their initial world positions, and the rest lengths of the Links and Bends
+	Points []math32.Vector3
+
+	// total mass of the points, which is divided evenly among them
+	Mass float32 `default:"0.1"`
+
+	// distance constraints between pairs of points that hold the structure together (e.g., the segments of a rope, and the edges and diagonals of cloth), which are drawn as lines if there are no Tris
+	Links []SoftLink
+
+	// softer distance constraints between pairs of points that resist bending (e.g., between every other point along a rope)
+	Bends []SoftLink
+
+	// triangles as triples of point indexes, which are drawn as a surface (e.g., for cloth)
+	Tris []int
+
+	// attachments of points to bodies, or to fixed positions in the world
+	Pins []SoftPin
+
+	// radius of each point for collisions with bodies, which is also the thickness of drawn lines
+	Radius float32 `default:"0.01"`
+
+	// proportion of the stretch of the Links that is corrected per iteration (0-1)
+	Stiff float32 `default:"1"`
+
+	// proportion of the stretch of the Bends that is corrected per iteration (0-1)
+	BendStiff float32 `default:"0.1"`
+
+	// number of iterations over all the constraints per step -- more iterations make the Links less stretchy
+	Iters int `default:"10"`
+
+	// proportion of the velocity of the points that is lost per unit time, e.g., from air resistance
+	Damping float32 `default:"0.5"`
+
+	// proportion of the motion of points along the surface of a body that is lost in contact with it
+	Friction float32 `default:"0.5"`
+
+	// color to draw in, as a standard color name or hex value
+	Color string
+
+	// current positions of the points in world coordinates
+	Pos []math32.Vector3 `set:"-" edit:"-"`
+
+	// current velocities of the points in world coordinates
+	Vel []math32.Vector3 `set:"-" edit:"-"`
+
+	// rest lengths of the Links and Bends, from the initial positions
+	linkLen, bendLen []float32
+
+	// index into Pins for each point, or -1 if free
+	pinOf []int
+}
+
+// SoftLink is a distance constraint between two points of a Soft body,
+// which keeps them at their initial distance
+type SoftLink struct {
+
+	// index of the first point
+	A int
+
+	// index of the second point
+	B int
+}
+
+// SoftPin attaches a point of a Soft body to a body, or to a fixed position
+type SoftPin struct {
+
+	// index of the point
+	Point int
+
+	// body that the point is attached to -- if nil, the point is fixed at its initial position in the world
+	Body Body
+
+	// position of the attachment in the local coordinates of the Body, or in world coordinates if the Body is nil, which is set by InitAbs to the initial position of the point
+	Local math32.Vector3
+}
+
+func (sf *Soft) EveNodeType() NodeTypes {
+	return SOFT
+}
+
+func (sf *Soft) Defaults() {
+	sf.Mass = 0.1
+	sf.Radius = 0.01
+	sf.Stiff = 1
+	sf.BendStiff = 0.1
+	sf.Iters = 10
+	sf.Damping = 0.5
+	sf.Friction = 0.5
+}
+
+func (sf *Soft) OnInit() {
+	sf.Defaults()
+	sf.SetFlag(true, Dynamic)
+}
+
+func (sf *Soft) GroupBBox() {
+}
+
+// Link adds a Link between given points
+func (sf *Soft) Link(a, b int) {
+	sf.Links = append(sf.Links, SoftLink{A: a, B: b})
+}
+
+// Bend adds a Bend between given points
+func (sf *Soft) Bend(a, b int) {
+	sf.Bends = append(sf.Bends, SoftLink{A: a, B: b})
+}
+
+// Pin attaches given point to given body, at given position in the local
+// coordinates of the body.  If the body is nil, the point is fixed at its
+// initial position in the world, or at its current position if the Soft
+// body has already been initialized.
+func (sf *Soft) Pin(pt int, bod Body, local math32.Vector3) {
+	if bod == nil && pt >= 0 && pt < len(sf.Pos) {
+		local = sf.Pos[pt]
+	}
+	sf.Pins = append(sf.Pins, SoftPin{Point: pt, Body: bod, Local: local})
+}
+
+// MakeRope configures the Soft body as a rope of n points from start
+// to end in local coordinates, with Links between successive points, and
+// Bends between every other point.
+func (sf *Soft) MakeRope(start, end math32.Vector3, n int) {
+	n = max(n, 2)
+	sf.Points = make([]math32.Vector3, n)
+	sf.Links, sf.Bends, sf.Tris = nil, nil, nil
+	for i := range n {
+		sf.Points[i] = start.Lerp(end, float32(i)/float32(n-1))
+		if i > 0 {
+			sf.Link(i-1, i)
+		}
+		if i > 1 {
+			sf.Bend(i-2, i)
+		}
+	}
+}
+
+// MakeCloth configures the Soft body as a rectangular sheet of cloth with
+// nu by nv points, starting at given corner and spanning the given u and v
+// edges in local coordinates.  Point (i, j) along (u, v) has index j*nu + i.
+// Links connect neighboring points and the diagonals of each cell, and
+// Bends connect every other point along u and v.
+func (sf *Soft) MakeCloth(corner, u, v math32.Vector3, nu, nv int) {
+	nu, nv = max(nu, 2), max(nv, 2)
+	sf.Points = make([]math32.Vector3, nu*nv)
+	sf.Links, sf.Bends, sf.Tris = nil, nil, nil
+	idx := func(i, j int) int { return j*nu + i }
+	for j := range nv {
+		for i := range nu {
+			fu, fv := float32(i)/float32(nu-1), float32(j)/float32(nv-1)
+			sf.Points[idx(i, j)] = corner.Add(u.MulScalar(fu)).Add(v.MulScalar(fv))
+			if i > 0 {
+				sf.Link(idx(i-1, j), idx(i, j))
+			}
+			if j > 0 {
+				sf.Link(idx(i, j-1), idx(i, j))
+			}
+			if i > 0 && j > 0 {
+				sf.Link(idx(i-1, j-1), idx(i, j))
+				sf.Link(idx(i, j-1), idx(i-1, j))
+				sf.Tris = append(sf.Tris, idx(i-1, j-1), idx(i, j-1), idx(i, j), idx(i-1, j-1), idx(i, j), idx(i-1, j))
+			}
+			if i > 1 {
+				sf.Bend(idx(i-2, j), idx(i, j))
+			}
+			if j > 1 {
+				sf.Bend(idx(i, j-2), idx(i, j))
+			}
+		}
+	}
+}
+
+func (sf *Soft) InitAbs(par *NodeBase) {
+	sf.InitAbsBase(par)
+	sc := sf.Abs.ScaleFactor()
+	n := len(sf.Points)
+	sf.Pos = make([]math32.Vector3, n)
+	sf.Vel = make([]math32.Vector3, n)
+	for i, p := range sf.Points {
+		sf.Pos[i] = p.Mul(sc).MulQuat(sf.Abs.Quat).Add(sf.Abs.Pos)
+	}
+	sf.linkLen = sf.restLengths(sf.Links)
+	sf.bendLen = sf.restLengths(sf.Bends)
+	for pi := range sf.Pins {
+		pn := &sf.Pins[pi]
+		if pn.Body == nil && pn.Point >= 0 && pn.Point < n {
+			pn.Local = sf.Pos[pn.Point]
+		}
+	}
+	sf.setPinOf()
+	sf.SetBBox()
+}
+
+// setPinOf sets the index into Pins for each point, so that Pins that
+// are added after InitAbs also take effect on the next step
+func (sf *Soft) setPinOf() {
+	n := len(sf.Pos)
+	if len(sf.pinOf) != n {
+		sf.pinOf = make([]int, n)
+	}
+	for i := range sf.pinOf {
+		sf.pinOf[i] = -1
+	}
+	for pi, pn := range sf.Pins {
+		if pn.Point >= 0 && pn.Point < n {
+			sf.pinOf[pn.Point] = pi
+		}
+	}
+}
+
+// restLengths returns the current distances between the points of given links
+func (sf *Soft) restLengths(links []SoftLink) []float32 {
+	ls := make([]float32, len(links))
+	for i, lk := range links {
+		ls[i] = sf.Pos[lk.A].DistanceTo(sf.Pos[lk.B])
+	}
+	return ls
+}
+
+func (sf *Soft) RelToAbs(par *NodeBase) {
+	sf.RelToAbsBase(par)
+}
+
+// StepPhys does nothing, as Soft bodies are only updated by the Solver
+func (sf *Soft) StepPhys(step float32) {
+}
+
+// SetBBox sets the bounding box around the current positions of the points
+func (sf *Soft) SetBBox() {
+	if len(sf.Pos) == 0 {
+		sf.BBox.SetBounds(sf.Abs.Pos, sf.Abs.Pos)
+	} else {
+		bb := math32.B3Empty()
+		bb.ExpandByPoints(sf.Pos)
+		r := math32.Vector3Scalar(sf.Radius)
+		sf.BBox.SetBounds(bb.Min.Sub(r), bb.Max.Add(r))
+	}
+	sf.BBox.OBB.Set(sf.BBox.BBox, math32.NewQuat(0, 0, 0, 1), math32.Vector3{})
+	sf.BBox.VelNilProject()
+}
+
+//////////////////////////////////////////////////////////////////////
+//  Solver
+
+// softPin is the working state of a Pin during a step
+type softPin struct {
+	pin *SoftPin
+
+	// working state of the body, if it is movable, else nil
+	sb *solverBody
+
+	// attachment point relative to the body center, in world coords
+	r math32.Vector3
+}
+
+// target returns the position of the attachment at the end of the step
+func (sp *softPin) target(step float32) math32.Vector3 {
+	if sp.pin.Body == nil {
+		return sp.pin.Local
+	}
+	pos := sp.pin.Body.AsNodeBase().Abs.Pos.Add(sp.r)
+	if sp.sb != nil {
+		pos.SetAdd(sp.sb.velAt(sp.r).MulScalar(step))
+	}
+	return pos
+}
+
+// StepSofts updates all the Soft bodies in the world by one step,
+// using the current velocities of the bodies that they are pinned to,
+// which are in turn updated by the pull of the Soft bodies on them.
+// This is called by Step prior to StepMovable.
+func (sv *Solver) StepSofts(world *Group, step float32) {
+	world.WalkDown(func(k tree.Node) bool {
+		nii, _ := AsNode(k)
+		if nii == nil {
+			return false // going into a different type of thing, bail
+		}
+		if !nii.IsDynamic() {
+			return false
+		}
+		if sf, ok := k.(*Soft); ok {
+			sv.StepSoft(world, sf, step)
+			return false
+		}
+		return true
+	})
+}
+
+// StepSoft updates given Soft body in given world by one step
+func (sv *Solver) StepSoft(world *Group, sf *Soft, step float32) {
+	n := len(sf.Pos)
+	if n == 0 || step <= 0 {
+		return
+	}
+	sf.setPinOf()
+	// pins on the same body share its solverBody, so that all of their
+	// impulses are applied to it
+	bods := make(map[Body]*solverBody)
+	pins := make([]softPin, len(sf.Pins))
+	for pi := range sf.Pins {
+		sp := &pins[pi]
+		sp.pin = &sf.Pins[pi]
+		bod := sp.pin.Body
+		if bod == nil {
+			continue
+		}
+		bb := bod.AsBodyBase()
+		sp.r = sp.pin.Local.Mul(bb.Abs.ScaleFactor()).MulQuat(bb.Abs.Quat)
+		if !IsMovable(bod) {
+			continue
+		}
+		sp.sb = bods[bod]
+		if sp.sb == nil {
+			sp.sb = sv.newSolverBody(bod, step)
+			bods[bod] = sp.sb
+		}
+	}
+	pinned := func(i int) *softPin {
+		if pi := sf.pinOf[i]; pi >= 0 {
+			return &pins[pi]
+		}
+		return nil
+	}
+
+	var w float32 // inverse mass of each free point
+	if sf.Mass > 0 {
+		w = float32(n) / sf.Mass
+	}
+	prev := make([]math32.Vector3, n)
+	copy(prev, sf.Pos)
+	damp := max(1-sf.Damping*step, 0)
+	dv := sv.Gravity.MulScalar(step)
+	for i := range sf.Pos {
+		if sp := pinned(i); sp != nil {
+			sf.Pos[i] = sp.target(step)
+			continue
+		}
+		sf.Vel[i] = sf.Vel[i].Add(dv).MulScalar(damp)
+		sf.Pos[i].SetAdd(sf.Vel[i].MulScalar(step))
+	}
+
+	bb := math32.B3Empty()
+	bb.ExpandByPoints(prev)
+	bb.ExpandByPoints(sf.Pos)
+	r := math32.Vector3Scalar(sf.Radius + sf.Radius)
+	bb.Min.SetSub(r)
+	bb.Max.SetAdd(r)
+	obs := world.BodiesInBox(bb, sf)
+
+	// project corrects the distance between points a and b toward rest length l
+	project := func(a, b int, l, stiff float32) {
+		d := sf.Pos[a].Sub(sf.Pos[b])
+		dl := d.Length()
+		if dl == 0 {
+			return
+		}
+		nd := d.DivScalar(dl)
+		pa, pb := pinned(a), pinned(b)
+		wa, wb := w, w
+		if pa != nil {
+			wa = pa.invMass(nd)
+		}
+		if pb != nil {
+			wb = pb.invMass(nd)
+		}
+		if wa+wb == 0 {
+			return
+		}
+		lam := -(dl - l) * stiff / (wa + wb)
+		sf.movePoint(a, pa, nd.MulScalar(lam), wa, step)
+		sf.movePoint(b, pb, nd.MulScalar(-lam), wb, step)
+	}
+
+	probe := &Sphere{Radius: sf.Radius}
+	contact := make([]math32.Vector3, n) // last contact normal of each point
+	for iter := 0; iter < sf.Iters; iter++ {
+		for li, lk := range sf.Links {
+			project(lk.A, lk.B, sf.linkLen[li], sf.Stiff)
+		}
+		for li, lk := range sf.Bends {
+			project(lk.A, lk.B, sf.bendLen[li], sf.BendStiff)
+		}
+		for i := range sf.Pos {
+			if pinned(i) != nil {
+				continue
+			}
+			sp := shapePose{bod: probe, pos: sf.Pos[i], quat: math32.NewQuat(0, 0, 0, 1), scale: math32.Vec3(1, 1, 1)}
+			for _, ob := range obs {
+				op := bodyPose(ob)
+				d, _, _, nrm := shapeDist(&sp, &op)
+				if d < 0 {
+					sp.pos.SetAdd(nrm.MulScalar(-d))
+					contact[i] = nrm
+				}
+			}
+			sf.Pos[i] = sp.pos
+		}
+	}
+
+	for i := range sf.Pos {
+		if sp := pinned(i); sp != nil {
+			sf.Pos[i] = sp.target(step)
+		} else if nrm := contact[i]; nrm != (math32.Vector3{}) {
+			mv := sf.Pos[i].Sub(prev[i])
+			tan := mv.Sub(nrm.MulScalar(mv.Dot(nrm)))
+			sf.Pos[i].SetSub(tan.MulScalar(sf.Friction))
+		}
+		sf.Vel[i] = sf.Pos[i].Sub(prev[i]).DivScalar(step)
+	}
+	for _, sb := range bods {
+		sb.store()
+	}
+	sf.SetBBox()
+}
+
+// invMass returns the inverse mass of the attachment along direction d
+func (sp *softPin) invMass(d math32.Vector3) float32 {
+	if sp.sb == nil {
+		return 0
+	}
+	return sp.sb.effMass(d, sp.r)
+}
+
+// movePoint moves point i by given correction times given inverse mass,
+// which is applied to the pinned body as an impulse if it is pinned
+func (sf *Soft) movePoint(i int, sp *softPin, corr math32.Vector3, w, step float32) {
+	if w == 0 {
+		return
+	}
+	if sp == nil {
+		sf.Pos[i].SetAdd(corr.MulScalar(w))
+		return
+	}
+	sp.sb.applyImpulse(corr.DivScalar(step), sp.r)
+	sf.Pos[i] = sp.target(step)
+}
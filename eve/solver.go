@@ -0,0 +1,741 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package eve
+
+import (
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tree"
+)
+
+// Solver resolves contacts between bodies for the Physics updating mode,
+// by applying impulses to the Abs.LinVel and Abs.AngVel of movable bodies
+// (see IsMovable), so that they bounce off of, slide and roll along each
+// other according to the combined Surface properties of their Materials.
+// Kinematic bodies, and any other Dynamic bodies without mass that are
+// moved by script (e.g., the contents of a moving Group), have infinite
+// mass, and push and carry movable bodies according to the velocity of
+// their scripted motion, so that bodies resting on moving platforms,
+// elevators and turntables move along with them through friction.
+type Solver struct {
+
+	// table of named materials, and rules for combining the surface properties of two bodies in contact
+	Materials Materials
+
+	// acceleration due to gravity, which is added to the velocity of movable bodies in Step
+	Gravity math32.Vector3
+
+	// constrains all dynamics to a plane, e.g., PlanarXZ for top-down navigation, eliminating drift and tipping over out of the plane -- this is combined with the per-body Rigid.LinLock and AngLock
+	Planar PlanarModes
+
+	// number of iterations over all contacts per step -- more iterations give more accurate results for stacks of bodies
+	Iters int `default:"8"`
+
+	// penetration depth that is allowed without correction, to avoid jitter for resting contacts
+	Slop float32 `default:"0.005"`
+
+	// proportion of the penetration beyond Slop that is corrected per step
+	Bias float32 `default:"0.2"`
+
+	// contacts with an approach velocity below this threshold do not bounce, so that bodies can come to rest
+	BounceThr float32 `default:"0.5"`
+
+	// distance beyond which points in the persistent contact Manifolds are dropped, and within which new points replace existing ones
+	ContactBreak float32 `default:"0.02"`
+
+	// proportion of the impulses from the last step that are applied at the start of the current step for persistent contact points, which greatly speeds convergence, e.g., for stacks of bodies
+	WarmStart float32 `default:"1"`
+
+	// broad phase of collision detection, which works for any layout of the world tree
+	Broad SpatialHash
+
+	// regions of fluid that apply buoyancy and drag to movable bodies in Step
+	Fluids []*Fluid
+
+	// bounds of the world, which can report, clamp or wrap around the objects that leave its Region, after they are stepped
+	Bounds Bounds
+
+	// wheeled vehicles whose wheels apply suspension and tire impulses to their chassis in Step
+	Vehicles []*Vehicle
+
+	// scripted objects that are snapped onto the ground at the start of each Step, before their Rel values are applied
+	Snaps []*GroundSnap `display:"-"`
+
+	// number of goroutines used to step the world in parallel, e.g., runtime.NumCPU() -- 0 or 1 is serial.  The independent subtrees under the world, the contacts of each body, and the islands of bodies connected by contacts are processed in parallel, with results identical to the serial run.
+	Workers int
+
+	// use the flat BodyStore in Store for stepping the movable bodies and for the list of colliding bodies in the broad phase, instead of walking the tree, which is faster for large numbers of bodies -- Store.Invalidate must be called when bodies are added or removed, or change their flags, mass, locks, scale or shape
+	Flat bool
+
+	// flat storage of the state of the movable bodies, used when Flat is set
+	Store BodyStore `display:"-"`
+
+	// persistent contact manifolds from the last step, by pair of bodies
+	Manifolds map[BodyPair]*Manifold `display:"-"`
+}
+
+func (sv *Solver) Defaults() {
+	sv.Materials.Defaults()
+	sv.Broad.MaxCells = 64
+	sv.Gravity.Set(0, -9.8, 0)
+	sv.Iters = 8
+	sv.Slop = 0.005
+	sv.Bias = 0.2
+	sv.BounceThr = 0.5
+	sv.ContactBreak = 0.02
+	sv.WarmStart = 1
+}
+
+// Step does one full update of the world in the Physics updating mode:
+// any Snaps snap their scripted objects onto the ground, WorldRelToAbs
+// applies any scripted changes to Rel values, including the motion of
+// Kinematic bodies, the wheels of any Vehicles apply their impulses,
+// Gravity is added to the velocities of movable bodies, along with
+// buoyancy and drag from any Fluids, contacts are collected using the
+// Broad SpatialHash, resolved using ResolveContacts, any Soft bodies are
+// updated by StepSofts, StepMovable updates positions from the resulting
+// velocities, the Bounds are applied, and then any Particles are updated
+// by StepParticles.  With Workers > 1, WorldRelToAbs, the Broad phase,
+// ResolveContacts and StepMovable run in parallel.  With Flat, the Broad
+// phase and StepMovable use the flat BodyStore in Store.
+// Returns the contacts from the Broad phase.
+func (sv *Solver) Step(world *Group, step float32) []Contacts {
+	for _, gs := range sv.Snaps {
+		gs.Snap(world)
+	}
+	world.WorldRelToAbsParallel(sv.Workers)
+	sv.ApplyVehicles(world, step)
+	sv.ApplyGravity(world, step)
+	sv.ApplyFluids(world, step)
+	sv.Broad.Bounds = &sv.Bounds
+	sv.Broad.Workers = sv.Workers
+	sv.Broad.Store = nil
+	if sv.Flat {
+		sv.Store.Sync(world, sv)
+		sv.Broad.Store = &sv.Store
+	}
+	cts := sv.Broad.Collide(world)
+	sv.ResolveContacts(cts, step)
+	sv.StepSofts(world, step)
+	sv.StepMovable(world, step)
+	sv.Bounds.Apply(world)
+	sv.StepParticles(world, step)
+	return cts
+}
+
+// StepMovable does StepPhys on all the movable bodies in the world,
+// updating their positions from their current velocities (subject to
+// their LockFactors), and updates the group bounding boxes.
+// Unlike WorldStepPhys, scripted Dynamic bodies without mass are not
+// stepped, as their Abs.LinVel reflects the motion that was already
+// applied by WorldRelToAbs.
+// The bodies are stepped in parallel with Workers > 1, and with Flat,
+// they are stepped using the flat BodyStore in Store.
+func (sv *Solver) StepMovable(world *Group, step float32) {
+	if sv.Flat {
+		sv.Store.Sync(world, sv)
+		sv.Store.Step(step, sv.Workers)
+		world.WorldDynGroupBBoxParallel(sv.Workers)
+		return
+	}
+	var bods []Body
+	world.WalkDown(func(k tree.Node) bool {
+		nii, _ := AsNode(k)
+		if nii == nil {
+			return false // going into a different type of thing, bail
+		}
+		if !nii.IsDynamic() {
+			return false
+		}
+		if bod := nii.AsBody(); bod != nil && IsMovable(bod) {
+			bods = append(bods, bod)
+		}
+		return true
+	})
+	parallelFor(len(bods), sv.Workers, func(i int) {
+		sv.ApplyLocks(bods[i].AsBodyBase())
+		bods[i].StepPhys(step)
+	})
+	world.WorldDynGroupBBoxParallel(sv.Workers)
+}
+
+// LockFactors returns the factors that multiply linear and angular
+// motion along each axis of given body, combining its Rigid.LinLock and
+// AngLock with the Planar mode: 1 = free, 0 = locked.
+func (sv *Solver) LockFactors(bb *BodyBase) (lin, ang math32.Vector3) {
+	lin, ang = bb.Rigid.LockFactors()
+	pl, pa := sv.Planar.Factors()
+	return lin.Mul(pl), ang.Mul(pa)
+}
+
+// ApplyLocks multiplies the Abs velocities of given body by its LockFactors
+func (sv *Solver) ApplyLocks(bb *BodyBase) {
+	lin, ang := sv.LockFactors(bb)
+	bb.Abs.LinVel.SetMul(lin)
+	bb.Abs.AngVel.SetMul(ang)
+}
+
+// ApplyGravity adds Gravity to the velocities of all movable bodies in the world,
+// subject to their LockFactors.
+func (sv *Solver) ApplyGravity(world *Group, step float32) {
+	dv := sv.Gravity.MulScalar(step)
+	world.WalkDown(func(k tree.Node) bool {
+		nii, ni := AsNode(k)
+		if nii == nil {
+			return false // going into a different type of thing, bail
+		}
+		if !nii.IsDynamic() {
+			return false
+		}
+		if bod := nii.AsBody(); bod != nil && IsMovable(bod) {
+			ni.Abs.LinVel.SetAdd(dv)
+			sv.ApplyLocks(bod.AsBodyBase())
+		}
+		return true
+	})
+}
+
+// solverBody is the working state of a body during contact resolution
+type solverBody struct {
+	bod     Body
+	invMass float32
+	invI    math32.Matrix3
+	linVel  math32.Vector3
+	angVel  math32.Vector3
+
+	// lock factors for linear and angular motion
+	linF, angF math32.Vector3
+}
+
+// newSolverBody returns the working state of given body for given step:
+// movable bodies have their mass, inertia, lock factors and velocities,
+// other Dynamic bodies (Kinematic or otherwise moved by script) have
+// infinite mass and the velocity of their scripted motion over the step,
+// and static bodies are fixed.
+func (sv *Solver) newSolverBody(bod Body, step float32) *solverBody {
+	sb := &solverBody{bod: bod}
+	bb := bod.AsBodyBase()
+	switch {
+	case IsMovable(bod):
+		sb.invMass = bb.ScaledInvMass()
+		sb.invI = bb.Rigid.InvRotInertia(bb.Abs.Quat)
+		sb.linF, sb.angF = sv.LockFactors(bb)
+		sb.linVel = bb.Abs.LinVel.Mul(sb.linF)
+		sb.angVel = bb.Abs.AngVel.Mul(sb.angF)
+	case bb.IsDynamic():
+		// infinite mass, with velocity from the scripted motion over the step
+		sb.linVel = bb.Abs.LinVel.DivScalar(step)
+		sb.angVel = bb.scriptAngVel.DivScalar(step)
+	}
+	return sb
+}
+
+// store sets the Abs velocities of a movable body from the working state
+func (sb *solverBody) store() {
+	if sb.invMass == 0 {
+		return
+	}
+	nb := sb.bod.AsNodeBase()
+	nb.Abs.LinVel = sb.linVel
+	nb.Abs.AngVel = sb.angVel
+}
+
+// applyImpulse applies impulse p at relative position r
+func (sb *solverBody) applyImpulse(p, r math32.Vector3) {
+	if sb.invMass == 0 {
+		return
+	}
+	sb.linVel.SetAdd(p.MulScalar(sb.invMass).Mul(sb.linF))
+	sb.angVel.SetAdd(r.Cross(p).MulMatrix3(&sb.invI).Mul(sb.angF))
+}
+
+// velAt returns the velocity of the body at relative position r
+func (sb *solverBody) velAt(r math32.Vector3) math32.Vector3 {
+	return sb.linVel.Add(sb.angVel.Cross(r))
+}
+
+// effMass returns the inverse effective mass along direction d at relative position r
+func (sb *solverBody) effMass(d, r math32.Vector3) float32 {
+	if sb.invMass == 0 {
+		return 0
+	}
+	rd := r.Cross(d)
+	lin := d.Mul(sb.linF).Dot(d) * sb.invMass
+	return lin + rd.MulMatrix3(&sb.invI).Mul(sb.angF).Cross(r).Dot(d)
+}
+
+// applyAngImpulse applies angular impulse l
+func (sb *solverBody) applyAngImpulse(l math32.Vector3) {
+	if sb.invMass == 0 {
+		return
+	}
+	sb.angVel.SetAdd(l.MulMatrix3(&sb.invI).Mul(sb.angF))
+}
+
+// angEffMass returns the inverse effective moment of inertia about axis d
+func (sb *solverBody) angEffMass(d math32.Vector3) float32 {
+	if sb.invMass == 0 {
+		return 0
+	}
+	return d.MulMatrix3(&sb.invI).Mul(sb.angF).Dot(d)
+}
+
+// solverContact is the working state of a contact point during resolution
+type solverContact struct {
+	ct     *Contact
+	mp     *ManifoldPoint
+	a, b   *solverBody
+	ra, rb math32.Vector3
+
+	// target minimum normal velocity
+	target float32
+
+	// true if the bodies are actually touching, not just about to
+	touching bool
+
+	// accumulated normal impulse
+	impN float32
+
+	// tangent axes for friction, with t1 along any anisotropic FrictionDir
+	t1, t2 math32.Vector3
+
+	// dynamic and static friction coefficients along t1 and t2
+	mu1, mu2, mus1, mus2 float32
+
+	// accumulated friction impulses along t1 and t2
+	imp1, imp2 float32
+
+	// velocity of the surface of A vs. B, relative to the bodies (see Rigid.SurfaceVel)
+	surfVel math32.Vector3
+
+	// accumulated rolling friction angular impulse
+	impR math32.Vector3
+
+	// accumulated spinning friction angular impulse
+	impS float32
+}
+
+// setFriction sets the friction tangent axes and coefficients, using the
+// FrictionDir of body A, or else B, for anisotropic friction, and the
+// relative velocity of their surfaces, from Rigid.SurfaceVel.
+func (sc *solverContact) setFriction() {
+	c := sc.ct
+	n := sc.mp.NormB
+	sc.surfVel = surfaceVel(c.A).Sub(surfaceVel(c.B))
+	sc.surfVel.SetSub(n.MulScalar(sc.surfVel.Dot(n)))
+	scale := float32(1)
+	for _, bod := range []Body{c.A, c.B} {
+		bb := bod.AsBodyBase()
+		if bb.Rigid.FrictionDir == (math32.Vector3{}) {
+			continue
+		}
+		d := bb.Rigid.FrictionDir.MulQuat(bb.Abs.Quat)
+		d.SetSub(n.MulScalar(d.Dot(n)))
+		if d.Length() < 1.0e-6 {
+			continue
+		}
+		sc.t1 = d.Normal()
+		scale = bb.Rigid.DirScale()
+		break
+	}
+	if sc.t1 == (math32.Vector3{}) {
+		sc.t1 = tangentTo(n)
+	}
+	sc.t2 = n.Cross(sc.t1)
+	sc.mu1 = c.Friction * scale
+	sc.mu2 = c.Friction
+	st := c.Static()
+	sc.mus1 = st * scale
+	sc.mus2 = st
+}
+
+// surfaceVel returns the Rigid.SurfaceVel of given body in world coordinates
+func surfaceVel(bod Body) math32.Vector3 {
+	bb := bod.AsBodyBase()
+	if bb.Rigid.SurfaceVel == (math32.Vector3{}) {
+		return math32.Vector3{}
+	}
+	return bb.Rigid.SurfaceVel.MulQuat(bb.Abs.Quat)
+}
+
+// tangentTo returns an arbitrary unit vector perpendicular to unit vector n
+func tangentTo(n math32.Vector3) math32.Vector3 {
+	if math32.Abs(n.X) < 0.57 {
+		return n.Cross(math32.Vec3(1, 0, 0)).Normal()
+	}
+	return n.Cross(math32.Vec3(0, 1, 0)).Normal()
+}
+
+// relVel returns the relative velocity of A vs. B at the contact
+func (sc *solverContact) relVel() math32.Vector3 {
+	return sc.a.velAt(sc.ra).Sub(sc.b.velAt(sc.rb))
+}
+
+// ResolveContacts computes the narrow-phase contact geometry for each
+// of the given contacts (as returned by SpatialHash.Collide or
+// WorldCollide), updates the persistent contact Manifold for each pair
+// of bodies, and applies impulses to the movable bodies to prevent them
+// from interpenetrating, using sequential impulses with accumulated
+// clamping over Iters iterations, warm started from the impulses of the
+// last step.
+// Bodies that are about to come into contact within the step are also
+// constrained (speculative contacts), so that fast bodies do not tunnel.
+// With Workers > 1, the Manifolds are updated in parallel, and the
+// islands of contacts that are connected by movable bodies are solved
+// in parallel.
+func (sv *Solver) ResolveContacts(cts []Contacts, step float32) {
+	bods := make(map[Body]*solverBody)
+	getBody := func(bod Body) *solverBody {
+		if sb, ok := bods[bod]; ok {
+			return sb
+		}
+		sb := sv.newSolverBody(bod, step)
+		bods[bod] = sb
+		return sb
+	}
+
+	if sv.Manifolds == nil {
+		sv.Manifolds = make(map[BodyPair]*Manifold)
+	}
+	// the contacts to resolve, and their indexes grouped by manifold,
+	// which are updated in parallel
+	type resolveContact struct {
+		c      *Contact
+		sa, sb *solverBody
+		scs    []*solverContact
+	}
+	var rcs []resolveContact
+	var mfs []*Manifold
+	var mfcs [][]int
+	mfi := make(map[BodyPair]int)
+	for _, cl := range cts {
+		for _, c := range cl {
+			sa := getBody(c.A)
+			sb := getBody(c.B)
+			if sa.invMass == 0 && sb.invMass == 0 {
+				continue
+			}
+			key := BodyPair{A: c.A, B: c.B, Offset: c.Offset}
+			mi, ok := mfi[key]
+			if !ok {
+				mf := sv.Manifolds[key]
+				if mf == nil {
+					mf = NewManifold(c.A, c.B, c.Offset)
+					sv.Manifolds[key] = mf
+				}
+				mi = len(mfs)
+				mfi[key] = mi
+				mfs = append(mfs, mf)
+				mfcs = append(mfcs, nil)
+			}
+			mfcs[mi] = append(mfcs[mi], len(rcs))
+			rcs = append(rcs, resolveContact{c: c, sa: sa, sb: sb})
+		}
+	}
+	// the solver contacts point into the manifold points, so they are
+	// only made once all contacts for the manifold have updated it
+	parallelFor(len(mfs), sv.Workers, func(mi int) {
+		mf := mfs[mi]
+		for _, ri := range mfcs[mi] {
+			c := rcs[ri].c
+			c.UpdtDist()
+			c.SetMaterials(&sv.Materials)
+			mf.Update(c, sv.ContactBreak)
+		}
+		rc := &rcs[mfcs[mi][len(mfcs[mi])-1]]
+		for pi := range mf.Points {
+			if sc := sv.newContact(rc.c, &mf.Points[pi], rc.sa, rc.sb, step); sc != nil {
+				rc.scs = append(rc.scs, sc)
+			}
+		}
+	})
+	for key, mf := range sv.Manifolds {
+		if _, seen := mfi[key]; !seen || len(mf.Points) == 0 {
+			delete(sv.Manifolds, key)
+		}
+	}
+	var scs []*solverContact
+	for ri := range rcs {
+		scs = append(scs, rcs[ri].scs...)
+	}
+	if len(scs) == 0 {
+		return
+	}
+
+	if sv.Workers > 1 {
+		isls := islands(scs)
+		parallelFor(len(isls), sv.Workers, func(i int) {
+			sv.solveContacts(isls[i])
+		})
+	} else {
+		sv.solveContacts(scs)
+	}
+	for _, sc := range scs {
+		mp := sc.mp
+		mp.ImpN = sc.impN
+		mp.ImpT = sc.t1.MulScalar(sc.imp1).Add(sc.t2.MulScalar(sc.imp2))
+		mp.ImpRoll = sc.impR
+		mp.ImpSpin = sc.impS
+	}
+
+	for _, sb := range bods {
+		sb.store()
+	}
+}
+
+// solveContacts warm starts and then iteratively solves given contacts
+func (sv *Solver) solveContacts(scs []*solverContact) {
+	for _, sc := range scs {
+		sv.warmStart(sc)
+	}
+	for iter := 0; iter < sv.Iters; iter++ {
+		for _, sc := range scs {
+			sv.solveContact(sc)
+		}
+	}
+}
+
+// islands partitions given contacts into islands, whose contacts are
+// connected by shared movable bodies, and that do not share any movable
+// bodies with other islands, so that they can be solved independently,
+// with the same results as solving all of the contacts together.
+// The islands are in order of their first contact, with their contacts
+// in the given order.
+func islands(scs []*solverContact) [][]*solverContact {
+	par := make(map[*solverBody]*solverBody)
+	find := func(b *solverBody) *solverBody {
+		if _, ok := par[b]; !ok {
+			par[b] = b
+		}
+		for par[b] != b {
+			par[b] = par[par[b]]
+			b = par[b]
+		}
+		return b
+	}
+	for _, sc := range scs {
+		if sc.a.invMass != 0 && sc.b.invMass != 0 {
+			par[find(sc.a)] = find(sc.b)
+		}
+	}
+	idx := make(map[*solverBody]int)
+	var isls [][]*solverContact
+	for _, sc := range scs {
+		mb := sc.a
+		if mb.invMass == 0 {
+			mb = sc.b
+		}
+		r := find(mb)
+		i, ok := idx[r]
+		if !ok {
+			i = len(isls)
+			idx[r] = i
+			isls = append(isls, nil)
+		}
+		isls[i] = append(isls[i], sc)
+	}
+	return isls
+}
+
+// newContact returns a new solverContact for given manifold point of
+// given contact, or nil if the bodies are not going to touch there within
+// the step, in which case the cached impulses of the point are cleared.
+func (sv *Solver) newContact(c *Contact, mp *ManifoldPoint, sa, sb *solverBody, step float32) *solverContact {
+	sc := &solverContact{ct: c, mp: mp, a: sa, b: sb}
+	sc.ra = mp.PtA.Sub(c.A.AsNodeBase().Abs.Pos)
+	sc.rb = mp.PtB.Sub(c.B.AsNodeBase().Abs.Pos.Add(c.Offset))
+	vn := sc.relVel().Dot(mp.NormB)
+	if mp.Dist > 0 {
+		if mp.Dist+vn*step > sv.Slop {
+			mp.ImpN, mp.ImpT, mp.ImpRoll, mp.ImpSpin = 0, math32.Vector3{}, math32.Vector3{}, 0
+			return nil // not going to touch this step
+		}
+		sc.target = -mp.Dist / step
+	} else {
+		sc.target = sv.Bias * max(-mp.Dist-sv.Slop, 0) / step
+		if vn < -sv.BounceThr {
+			sc.target = max(sc.target, -c.Bounce*vn)
+		}
+	}
+	sc.touching = mp.Dist <= sv.Slop
+	sc.setFriction()
+	return sc
+}
+
+// warmStart applies the impulses cached in the manifold point from
+// the last step, scaled by WarmStart, as the initial accumulated impulses.
+func (sv *Solver) warmStart(sc *solverContact) {
+	mp := sc.mp
+	ws := sv.WarmStart
+	sc.impN = mp.ImpN * ws
+	if sc.touching {
+		sc.imp1 = mp.ImpT.Dot(sc.t1) * ws
+		sc.imp2 = mp.ImpT.Dot(sc.t2) * ws
+		sc.impR = mp.ImpRoll.MulScalar(ws)
+		sc.impS = mp.ImpSpin * ws
+	}
+	n := mp.NormB
+	p := n.MulScalar(sc.impN).Add(sc.t1.MulScalar(sc.imp1)).Add(sc.t2.MulScalar(sc.imp2))
+	sc.a.applyImpulse(p, sc.ra)
+	sc.b.applyImpulse(p.Negate(), sc.rb)
+	l := sc.impR.Add(n.MulScalar(sc.impS))
+	sc.a.applyAngImpulse(l)
+	sc.b.applyAngImpulse(l.Negate())
+}
+
+// solveContact does one iteration of impulse computation for one contact
+func (sv *Solver) solveContact(sc *solverContact) {
+	n := sc.mp.NormB
+	kn := sc.a.effMass(n, sc.ra) + sc.b.effMass(n, sc.rb)
+	if kn <= 0 {
+		return
+	}
+	vn := sc.relVel().Dot(n)
+	dj := (sc.target - vn) / kn
+	nimp := max(sc.impN+dj, 0)
+	dj = nimp - sc.impN
+	sc.impN = nimp
+	p := n.MulScalar(dj)
+	sc.a.applyImpulse(p, sc.ra)
+	sc.b.applyImpulse(p.Negate(), sc.rb)
+
+	if !sc.touching {
+		return
+	}
+	sv.solveFriction(sc)
+	sv.solveRolling(sc)
+}
+
+// solveFriction does one iteration of the friction impulses along the two
+// tangent axes of the contact.  The accumulated impulses stay within the
+// static friction ellipse if possible, and otherwise are scaled back to
+// the dynamic friction ellipse, so that sliding bodies experience
+// dynamic friction.
+func (sv *Solver) solveFriction(sc *solverContact) {
+	if sc.mus1 == 0 && sc.mus2 == 0 {
+		return
+	}
+	vr := sc.relVel().Add(sc.surfVel)
+	j1 := sc.imp1 + sv.frictionImpulse(sc, sc.t1, vr, sc.mus1)
+	j2 := sc.imp2 + sv.frictionImpulse(sc, sc.t2, vr, sc.mus2)
+	if ellipseNorm(j1, j2, sc.mus1, sc.mus2) > sc.impN {
+		if en := ellipseNorm(j1, j2, sc.mu1, sc.mu2); en > 0 {
+			f := sc.impN / en
+			j1 *= f
+			j2 *= f
+		}
+	}
+	if sc.mu1 == 0 {
+		j1 = 0
+	}
+	if sc.mu2 == 0 {
+		j2 = 0
+	}
+	p := sc.t1.MulScalar(j1 - sc.imp1).Add(sc.t2.MulScalar(j2 - sc.imp2))
+	sc.imp1, sc.imp2 = j1, j2
+	sc.a.applyImpulse(p, sc.ra)
+	sc.b.applyImpulse(p.Negate(), sc.rb)
+}
+
+// frictionImpulse returns the impulse along tangent t that would stop
+// the relative velocity vr along t, or 0 if mu is 0
+func (sv *Solver) frictionImpulse(sc *solverContact, t, vr math32.Vector3, mu float32) float32 {
+	if mu == 0 {
+		return 0
+	}
+	kt := sc.a.effMass(t, sc.ra) + sc.b.effMass(t, sc.rb)
+	if kt <= 0 {
+		return 0
+	}
+	return -vr.Dot(t) / kt
+}
+
+// ellipseNorm returns the norm of impulse (j1, j2) relative to the
+// friction ellipse with coefficients mu1, mu2, in units of the normal
+// impulse.  An axis with a 0 coefficient is ignored.
+func ellipseNorm(j1, j2, mu1, mu2 float32) float32 {
+	var e float32
+	if mu1 > 0 {
+		e += (j1 / mu1) * (j1 / mu1)
+	}
+	if mu2 > 0 {
+		e += (j2 / mu2) * (j2 / mu2)
+	}
+	return math32.Sqrt(e)
+}
+
+// solveRolling does one iteration of the rolling and spinning friction
+// angular impulses, which oppose the relative angular velocity of the
+// bodies perpendicular to and about the contact normal, respectively.
+func (sv *Solver) solveRolling(sc *solverContact) {
+	c := sc.ct
+	n := sc.mp.NormB
+	if c.RollFriction > 0 {
+		w := sc.a.angVel.Sub(sc.b.angVel)
+		wr := w.Sub(n.MulScalar(w.Dot(n)))
+		if wl := wr.Length(); wl > 1.0e-6 {
+			u := wr.DivScalar(wl)
+			if k := sc.a.angEffMass(u) + sc.b.angEffMass(u); k > 0 {
+				imp := sc.impR.Sub(u.MulScalar(wl / k))
+				maxr := c.RollFriction * sc.impN
+				if il := imp.Length(); il > maxr {
+					imp.SetMulScalar(maxr / il)
+				}
+				l := imp.Sub(sc.impR)
+				sc.impR = imp
+				sc.a.applyAngImpulse(l)
+				sc.b.applyAngImpulse(l.Negate())
+			}
+		}
+	}
+	if c.SpinFriction > 0 {
+		ws := sc.a.angVel.Sub(sc.b.angVel).Dot(n)
+		if k := sc.a.angEffMass(n) + sc.b.angEffMass(n); k > 0 {
+			maxs := c.SpinFriction * sc.impN
+			imp := math32.Clamp(sc.impS-ws/k, -maxs, maxs)
+			l := n.MulScalar(imp - sc.impS)
+			sc.impS = imp
+			sc.a.applyAngImpulse(l)
+			sc.b.applyAngImpulse(l.Negate())
+		}
+	}
+}
+
+//////////////////////////////////////////////////////////////////////
+// PlanarModes
+
+// PlanarModes are ways of constraining all dynamics to a plane
+type PlanarModes int32 //enums:enum
+
+const (
+	// NoPlanar does not constrain dynamics
+	NoPlanar PlanarModes = iota
+
+	// PlanarXZ constrains motion to the horizontal X-Z plane, with rotation
+	// only about the vertical Y axis, e.g., for top-down navigation as
+	// shown with the eve2d View ProjectXZ.
+	PlanarXZ
+
+	// PlanarXY constrains motion to the X-Y plane, with rotation only
+	// about the Z axis.
+	PlanarXY
+
+	// PlanarYZ constrains motion to the Y-Z plane, with rotation only
+	// about the X axis.
+	PlanarYZ
+)
+
+// Factors returns the factors that multiply linear and angular
+// motion along each axis for this mode: 1 = free, 0 = locked.
+func (pm PlanarModes) Factors() (lin, ang math32.Vector3) {
+	switch pm {
+	case PlanarXZ:
+		return math32.Vec3(1, 0, 1), math32.Vec3(0, 1, 0)
+	case PlanarXY:
+		return math32.Vec3(1, 1, 0), math32.Vec3(0, 0, 1)
+	case PlanarYZ:
+		return math32.Vec3(0, 1, 1), math32.Vec3(1, 0, 0)
+	}
+	return math32.Vector3Scalar(1), math32.Vector3Scalar(1)
+}
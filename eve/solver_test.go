@@ -0,0 +1,92 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package eve
+
+import (
+	"testing"
+
+	"cogentcore.org/core/math32"
+)
+
+func TestShapeDist(t *testing.T) {
+	w := newTestWorld()
+	a := newTestBox(w, "a", math32.Vec3(0, 0, 0), math32.Vec3(1, 1, 1))
+	b := newTestBox(w, "b", math32.Vec3(1.5, 0, 0), math32.Vec3(1, 1, 1))
+	c := newTestBox(w, "c", math32.Vec3(0.8, 0.2, 0), math32.Vec3(1, 1, 1))
+	sp := NewSphere(w, "s")
+	sp.Radius = 0.5
+	sp.SetInitPos(math32.Vec3(0, 2, 0))
+	w.WorldInit()
+
+	tests := []struct {
+		a, b Body
+		dist float32
+		norm math32.Vector3
+	}{
+		{a, b, 0.5, math32.Vec3(-1, 0, 0)},
+		{b, a, 0.5, math32.Vec3(1, 0, 0)},
+		{a, c, -0.2, math32.Vec3(-1, 0, 0)},
+		{sp, a, 1, math32.Vec3(0, 1, 0)},
+	}
+	for i, tt := range tests {
+		d, ptA, ptB, n := ShapeDist(tt.a, tt.b)
+		if math32.Abs(d-tt.dist) > 1.0e-3 {
+			t.Errorf("%d: dist: got %g, want %g", i, d, tt.dist)
+		}
+		if n.Sub(tt.norm).Length() > 1.0e-3 {
+			t.Errorf("%d: normal: got %v, want %v", i, n, tt.norm)
+		}
+		if ptB.Add(n.MulScalar(d)).Sub(ptA).Length() > 1.0e-3 {
+			t.Errorf("%d: points %v and %v are not dist %g apart along the normal", i, ptA, ptB, d)
+		}
+	}
+}
+
+func TestRestingBox(t *testing.T) {
+	w := newTestWorld()
+	fl := newTestBox(w, "floor", math32.Vec3(0, -0.5, 0), math32.Vec3(20, 1, 20))
+	fl.Rigid.Friction = 0.5
+	bx := newTestBox(w, "box", math32.Vec3(0, 0.5, 0), math32.Vec3(1, 1, 1))
+	bx.Rigid.SetMass(1)
+	bx.Rigid.Friction = 0.5
+	bx.SetDynamic()
+	w.WorldInit()
+	sv := &Solver{}
+	sv.Defaults()
+	for range 200 {
+		sv.Step(w, 0.01)
+	}
+	if d := bx.Abs.Pos.Sub(math32.Vec3(0, 0.5, 0)).Length(); d > 0.01 {
+		t.Errorf("box moved from rest to %v", bx.Abs.Pos)
+	}
+	if v := bx.Abs.LinVel.Length() + bx.Abs.AngVel.Length(); v > 1.0e-3 {
+		t.Errorf("box is not at rest: velocities %v %v", bx.Abs.LinVel, bx.Abs.AngVel)
+	}
+	if len(sv.Manifolds) != 1 {
+		t.Fatalf("got %d manifolds, want 1", len(sv.Manifolds))
+	}
+	for _, mf := range sv.Manifolds {
+		if len(mf.Points) != MaxManifoldPoints {
+			t.Errorf("got %d manifold points, want %d", len(mf.Points), MaxManifoldPoints)
+		}
+	}
+}
+
+func TestFallingBoxLands(t *testing.T) {
+	w := newTestWorld()
+	newTestBox(w, "floor", math32.Vec3(0, -0.5, 0), math32.Vec3(20, 1, 20))
+	bx := newTestBox(w, "box", math32.Vec3(0, 3, 0), math32.Vec3(1, 1, 1))
+	bx.Rigid.SetMass(1)
+	bx.SetDynamic()
+	w.WorldInit()
+	sv := &Solver{}
+	sv.Defaults()
+	for range 300 {
+		sv.Step(w, 0.01)
+	}
+	if math32.Abs(bx.Abs.Pos.Y-0.5) > 0.01 {
+		t.Errorf("box did not land on the floor: %v", bx.Abs.Pos)
+	}
+}
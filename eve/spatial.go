@@ -0,0 +1,350 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package eve
+
+import (
+	"slices"
+
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tree"
+)
+
+// SpatialHash is a uniform grid of cells in world coords, which is used
+// to find the potential contacts between bodies for any layout of the
+// world tree, unlike WorldCollide, which depends on dynamic bodies being
+// organized into separate groups.  It is rebuilt from the flags and
+// velocity-projected bounding boxes of all the bodies on each call to Collide.
+type SpatialHash struct {
+
+	// size of each cell -- if 0, it is set automatically to twice the average size of the dynamic bodies
+	CellSize float32
+
+	// maximum number of cells that a body can span before it is instead tested directly against all other bodies (e.g., large floors and walls)
+	MaxCells int `default:"64"`
+
+	// cell size used for the current hash
+	cell float32
+
+	// indexes of bodies in each cell
+	cells map[hashKey][]int
+
+	// all the bodies in the world, in tree order
+	bods []hashBody
+
+	// indexes of bodies that span more than MaxCells, or have empty bounding boxes
+	big []int
+
+	// bounds of the world, for finding contacts across the edges of a world that wraps around -- set from Solver.Bounds in Solver.Step
+	Bounds *Bounds `display:"-"`
+
+	// number of goroutines used to find the contacts of the dynamic bodies in parallel -- 0 or 1 is serial -- set from Solver.Workers in Solver.Step
+	Workers int `display:"-"`
+
+	// if non-nil and built for the world, its list of colliding bodies is used instead of walking the tree -- set from Solver.Store in Solver.Step when Solver.Flat is set
+	Store *BodyStore `display:"-"`
+}
+
+// hashKey is the integer coordinates of a cell
+type hashKey [3]int32
+
+// maxHashCoord is the largest magnitude of the integer coordinates of
+// a cell, to which the coordinates of very large or infinite boxes are
+// clamped, so that they fit in a hashKey
+const maxHashCoord = 1 << 24
+
+// hashBody is a body in the SpatialHash
+type hashBody struct {
+	bod Body
+
+	// top-level group under the world that contains the body
+	top tree.Node
+
+	// highest group under the world that contains the body and is a
+	// scripted object (see scriptedGroup), or nil if none
+	obj tree.Node
+
+	// dynamic and movable status
+	dyn, movable bool
+
+	// true if in the big list
+	big bool
+}
+
+// Collide returns the list of potential contacts between bodies in given
+// world, as the bodies whose velocity-projected bounding boxes (VelBBox)
+// and oriented bounding boxes (VelOBB) intersect.  A is always a Dynamic
+// body, and B is either a static body, or a Dynamic body that comes
+// before A in the tree.  Dynamic bodies that are not movable (see IsMovable),
+// and are within the same group whose bodies are all Dynamic and not
+// movable (the highest such group under the world), are assumed to be
+// parts of the same scripted object (e.g., an agent), and do not collide
+// with each other.  Static bodies collide with all Dynamic bodies,
+// wherever they are in the tree.  Contacts are organized by the top-level group
+// of A, in tree order, as in WorldCollide.  If Bounds wraps around, then
+// contacts are also found across the edges of its Region, with the
+// Contact.Offset of B, except with static bodies that reach the edges of
+// the Region on a wrapped axis (e.g., a floor that covers it), which are
+// only collided with directly.  The contacts of each dynamic body are
+// found in parallel when Workers > 1, with the same results.
+func (sh *SpatialHash) Collide(world *Group) []Contacts {
+	sh.Build(world)
+	found := make([]Contacts, len(sh.bods))
+	wrap := sh.Bounds != nil && sh.Bounds.Wraps()
+	parallelChunks(len(sh.bods), sh.Workers, func(st, ed int) {
+		hs := &hashScratch{mark: make([]int, len(sh.bods))}
+		for i := st; i < ed; i++ {
+			if sh.bods[i].dyn {
+				sh.collideBody(i, wrap, hs, &found[i])
+			}
+		}
+	})
+	var cts []Contacts
+	tops := make(map[tree.Node]int)
+	for i, fc := range found {
+		if len(fc) == 0 {
+			continue
+		}
+		top := sh.bods[i].top
+		ti, ok := tops[top]
+		if !ok {
+			ti = len(cts)
+			tops[top] = ti
+			cts = append(cts, nil)
+		}
+		cts[ti] = append(cts[ti], fc...)
+	}
+	return cts
+}
+
+// hashScratch is the working memory for finding the contacts of bodies
+type hashScratch struct {
+
+	// stamp of the last candidate search in which each body was added
+	mark []int
+
+	// current stamp
+	stamp int
+
+	// candidate bodies for the current search
+	cands []int
+}
+
+// collideBody adds the potential contacts of dynamic body i to cts,
+// directly and across the wrapped edges of the Bounds if wrap
+func (sh *SpatialHash) collideBody(i int, wrap bool, hs *hashScratch, cts *Contacts) {
+	hb := &sh.bods[i]
+	ab := hb.bod.AsNodeBase()
+	collide := func(off math32.Vector3) {
+		hs.cands = hs.cands[:0]
+		hs.stamp++
+		add := func(j int) {
+			if j == i || hs.mark[j] == hs.stamp {
+				return
+			}
+			hs.mark[j] = hs.stamp
+			if sh.bods[j].dyn && j > i {
+				return // handled as A for j
+			}
+			hs.cands = append(hs.cands, j)
+		}
+		if hb.big {
+			for j := range sh.bods {
+				add(j)
+			}
+		} else {
+			sh.forCells(ab.BBox.VelBBox.Translate(off.Negate()), func(k hashKey) {
+				for _, j := range sh.cells[k] {
+					add(j)
+				}
+			})
+			for _, j := range sh.big {
+				add(j)
+			}
+		}
+		slices.Sort(hs.cands)
+		for _, j := range hs.cands {
+			ob := &sh.bods[j]
+			if hb.dyn && ob.dyn && !hb.movable && !ob.movable && hb.obj != nil && hb.obj == ob.obj {
+				continue
+			}
+			bb := ob.bod.AsNodeBase().BBox
+			if off != (math32.Vector3{}) {
+				if !ob.dyn && sh.Bounds.atEdge(bb.BBox, off) {
+					continue
+				}
+				bb.Translate(off)
+			}
+			if !ab.BBox.IntersectsVelBox(&bb) || !ab.BBox.IntersectsVelOBB(&bb) {
+				continue
+			}
+			cts.New(hb.bod, ob.bod).Offset = off
+		}
+	}
+	collide(math32.Vector3{})
+	if wrap {
+		sh.Bounds.images(collide)
+	}
+}
+
+// Build rebuilds the hash from the current state of the bodies in given
+// world, including its Shared static geometry
+func (sh *SpatialHash) Build(world *Group) {
+	if sh.MaxCells == 0 {
+		sh.MaxCells = 64
+	}
+	sh.bods = sh.bods[:0]
+	sh.big = sh.big[:0]
+	if sh.cells == nil {
+		sh.cells = make(map[hashKey][]int)
+	}
+	for k, c := range sh.cells {
+		if len(c) == 0 { // not used in the last build
+			delete(sh.cells, k)
+			continue
+		}
+		sh.cells[k] = c[:0]
+	}
+	if sh.Store != nil && sh.Store.world == world {
+		sh.bods = append(sh.bods, sh.Store.colliders...)
+	} else {
+		sh.bods = hashBodies(world, sh.bods)
+	}
+	var dsz float32
+	ndyn := 0
+	for i := range sh.bods {
+		hb := &sh.bods[i]
+		vb := hb.bod.AsNodeBase().BBox.VelBBox
+		if !hb.dyn || vb.IsEmpty() {
+			continue
+		}
+		sz := vb.Size()
+		if m := max(sz.X, sz.Y, sz.Z); m < math32.Inf(1) {
+			dsz += m
+			ndyn++
+		}
+	}
+	sh.cell = sh.CellSize
+	if sh.cell <= 0 {
+		sh.cell = 1
+		if ndyn > 0 && dsz > 0 {
+			sh.cell = 2 * dsz / float32(ndyn)
+		}
+	}
+	for i := range sh.bods {
+		hb := &sh.bods[i]
+		vb := hb.bod.AsNodeBase().BBox.VelBBox
+		mn, mx := sh.key(vb.Min), sh.key(vb.Max)
+		n := float64(mx[0]-mn[0]+1) * float64(mx[1]-mn[1]+1) * float64(mx[2]-mn[2]+1)
+		if vb.IsEmpty() || n > float64(sh.MaxCells) {
+			hb.big = true
+			sh.big = append(sh.big, i)
+			continue
+		}
+		sh.forCells(vb, func(k hashKey) {
+			sh.cells[k] = append(sh.cells[k], i)
+		})
+	}
+}
+
+// hashBodies appends the colliding bodies in given world (see Collides),
+// including its Shared static geometry, to bods, in tree order
+func hashBodies(world *Group, bods []hashBody) []hashBody {
+	scripted := make(map[tree.Node]bool)
+	world.walkShared(func(k tree.Node) bool {
+		nii, _ := AsNode(k)
+		if nii == nil {
+			return false // going into a different type of thing, bail
+		}
+		if nii.EveNodeType() != BODY {
+			return true
+		}
+		bod := nii.AsBody()
+		if !Collides(bod) {
+			return false
+		}
+		hb := hashBody{bod: bod, dyn: nii.IsDynamic(), movable: IsMovable(bod)}
+		for p := k; p != nil && p != tree.Node(world); p = p.Parent() {
+			hb.top = p
+			if p != k && hb.dyn && !hb.movable && scriptedGroup(p, scripted) {
+				hb.obj = p
+			}
+		}
+		bods = append(bods, hb)
+		return false
+	})
+	return bods
+}
+
+// scriptedGroup returns true if given group is a scripted object: all of
+// the bodies within it are Dynamic and not movable (e.g., the parts of an
+// agent), so that they are moved together by script, and do not collide
+// with each other.  Results are cached in given map.
+func scriptedGroup(k tree.Node, scripted map[tree.Node]bool) bool {
+	if sc, ok := scripted[k]; ok {
+		return sc
+	}
+	sc := true
+	for _, kid := range *k.Children() {
+		nii, _ := AsNode(kid)
+		if nii == nil {
+			continue
+		}
+		if nii.EveNodeType() == BODY {
+			bod := nii.AsBody()
+			if Collides(bod) && (!nii.IsDynamic() || IsMovable(bod)) {
+				sc = false
+				break
+			}
+			continue
+		}
+		if !scriptedGroup(kid, scripted) {
+			sc = false
+			break
+		}
+	}
+	scripted[k] = sc
+	return sc
+}
+
+// key returns the key of the cell containing given point,
+// clamped to within maxHashCoord cells of the origin
+func (sh *SpatialHash) key(p math32.Vector3) hashKey {
+	return hashKey{sh.coord(p.X), sh.coord(p.Y), sh.coord(p.Z)}
+}
+
+// coord returns the integer coordinate of the cell containing given
+// coordinate, clamped to within maxHashCoord, including for infinities
+// and NaN (e.g., from an empty box)
+func (sh *SpatialHash) coord(v float32) int32 {
+	c := math32.Floor(v / sh.cell)
+	switch {
+	case !(c > -maxHashCoord): // also NaN
+		return -maxHashCoord
+	case c > maxHashCoord:
+		return maxHashCoord
+	}
+	return int32(c)
+}
+
+// forCells calls fun for each cell that overlaps given box
+func (sh *SpatialHash) forCells(box math32.Box3, fun func(k hashKey)) {
+	mn, mx := sh.key(box.Min), sh.key(box.Max)
+	for x := mn[0]; x <= mx[0]; x++ {
+		for y := mn[1]; y <= mx[1]; y++ {
+			for z := mn[2]; z <= mx[2]; z++ {
+				fun(hashKey{x, y, z})
+			}
+		}
+	}
+}
+
+// WorldCollideAll returns the potential contacts between all the bodies in
+// the world, for any layout of the world tree, using a new SpatialHash.
+// See SpatialHash.Collide for details.  Use a persistent SpatialHash
+// (e.g., in the Solver) to avoid reallocating the hash on each step.
+func (gp *Group) WorldCollideAll() []Contacts {
+	var sh SpatialHash
+	return sh.Collide(gp)
+}
@@ -0,0 +1,167 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package eve
+
+import (
+	"slices"
+	"testing"
+
+	"cogentcore.org/core/math32"
+)
+
+// newTestWorld returns a new empty world group
+func newTestWorld() *Group {
+	w := &Group{}
+	w.InitName(w, "world")
+	return w
+}
+
+// newTestBox adds a box of given size at given position to parent
+func newTestBox(par Node, name string, pos, size math32.Vector3) *Box {
+	bx := NewBox(par, name)
+	bx.Size = size
+	bx.SetInitPos(pos)
+	return bx
+}
+
+// contactPairs returns the sorted list of unique body name pairs in
+// given contacts, with the names of each pair in sorted order
+func contactPairs(cts []Contacts) []string {
+	var ps []string
+	for _, cl := range cts {
+		for _, c := range cl {
+			a, b := c.A.AsNodeBase().Name(), c.B.AsNodeBase().Name()
+			if b < a {
+				a, b = b, a
+			}
+			p := a + "-" + b
+			if !slices.Contains(ps, p) {
+				ps = append(ps, p)
+			}
+		}
+	}
+	slices.Sort(ps)
+	return ps
+}
+
+// makeStandardWorld makes a world with the static bodies in one top-level
+// group, and each dynamic object in its own top-level group, as required
+// by WorldCollide(DynsTopGps)
+func makeStandardWorld() *Group {
+	w := newTestWorld()
+	st := NewGroup(w, "static")
+	newTestBox(st, "floor", math32.Vec3(0, -0.5, 0), math32.Vec3(20, 1, 20))
+	newTestBox(st, "wall", math32.Vec3(5, 1, 0), math32.Vec3(1, 2, 20))
+
+	ag := NewGroup(w, "agent")
+	ag.SetFlag(true, Dynamic)
+	newTestBox(ag, "body", math32.Vec3(4.2, 0.5, 0), math32.Vec3(1, 1, 1)).SetDynamic()
+	hd := NewSphere(ag, "head")
+	hd.Radius = 0.3
+	hd.SetInitPos(math32.Vec3(4.2, 1.2, 0))
+	hd.SetDynamic()
+
+	for i, x := range []float32{-2, -1.1, 3} {
+		bg := NewGroup(w, "ball-group")
+		bg.SetFlag(true, Dynamic)
+		bl := NewSphere(bg, []string{"ball0", "ball1", "ball2"}[i])
+		bl.Radius = 0.5
+		bl.SetInitPos(math32.Vec3(x, 0.5, 0))
+		bl.Rigid.SetMass(1)
+		bl.SetDynamic()
+	}
+	w.WorldInit()
+	return w
+}
+
+func TestCollideAllMatchesWorldCollide(t *testing.T) {
+	w := makeStandardWorld()
+	want := contactPairs(w.WorldCollide(DynsTopGps))
+	got := contactPairs(w.WorldCollideAll())
+	if !slices.Equal(got, want) {
+		t.Errorf("WorldCollideAll pairs:\n%v\nWorldCollide pairs:\n%v", got, want)
+	}
+	for _, p := range []string{"body-wall", "body-floor", "ball0-ball1", "ball0-floor"} {
+		if !slices.Contains(got, p) {
+			t.Errorf("missing expected contact %s in %v", p, got)
+		}
+	}
+	if slices.Contains(got, "body-head") {
+		t.Errorf("parts of the same scripted object should not collide: %v", got)
+	}
+}
+
+func TestCollideAllSameGroup(t *testing.T) {
+	for _, kinematic := range []bool{false, true} {
+		w := newTestWorld()
+		lv := NewGroup(w, "level")
+		newTestBox(lv, "wall", math32.Vec3(0, 0, 0), math32.Vec3(1, 2, 4))
+		ag := newTestBox(lv, "agent", math32.Vec3(0.8, 0, 0), math32.Vec3(1, 1, 1))
+		if kinematic {
+			ag.SetKinematic()
+		} else {
+			ag.SetDynamic()
+		}
+		w.WorldInit()
+		got := contactPairs(w.WorldCollideAll())
+		if !slices.Equal(got, []string{"agent-wall"}) {
+			t.Errorf("kinematic: %v: scripted body in the same group as a wall: got contacts %v", kinematic, got)
+		}
+	}
+}
+
+func TestCollideAllNested(t *testing.T) {
+	w := newTestWorld()
+	lv := NewGroup(w, "level")
+	newTestBox(lv, "floor", math32.Vec3(0, -0.5, 0), math32.Vec3(20, 1, 20))
+	newTestBox(lv, "wall", math32.Vec3(5, 1, 0), math32.Vec3(1, 2, 20))
+	for i, x := range []float32{4.2, 4.3} {
+		ag := NewGroup(lv, []string{"agent0", "agent1"}[i])
+		ag.SetFlag(true, Dynamic)
+		newTestBox(ag, []string{"body0", "body1"}[i], math32.Vec3(x, 0.5, float32(i)*0.8), math32.Vec3(1, 1, 1)).SetDynamic()
+		hd := NewSphere(ag, []string{"head0", "head1"}[i])
+		hd.Radius = 0.3
+		hd.SetInitPos(math32.Vec3(x, 1.2, float32(i)*0.8))
+		hd.SetDynamic()
+	}
+	w.WorldInit()
+	got := contactPairs(w.WorldCollideAll())
+	for _, p := range []string{"body0-wall", "body0-floor", "body1-wall", "body0-body1", "body0-head1"} {
+		if !slices.Contains(got, p) {
+			t.Errorf("missing expected contact %s in %v", p, got)
+		}
+	}
+	for _, p := range []string{"body0-head0", "body1-head1"} {
+		if slices.Contains(got, p) {
+			t.Errorf("parts of the same scripted object should not collide: %s in %v", p, got)
+		}
+	}
+}
+
+func TestResolveOverlapsNested(t *testing.T) {
+	w := newTestWorld()
+	lv := NewGroup(w, "level")
+	wall := newTestBox(lv, "wall", math32.Vec3(5, 1, 0), math32.Vec3(1, 2, 20))
+	ag := NewGroup(lv, "agent")
+	body := newTestBox(ag, "body", math32.Vec3(4.2, 1, 0), math32.Vec3(1, 1, 1))
+	body.SetDynamic()
+	hd := NewSphere(ag, "head")
+	hd.Radius = 0.3
+	hd.SetInitPos(math32.Vec3(4.2, 1.7, 0))
+	hd.SetDynamic()
+	w.WorldInit()
+	if n := w.WorldResolveOverlaps(10); n != 0 {
+		t.Errorf("overlaps remaining: %d", n)
+	}
+	if wall.Abs.Pos != math32.Vec3(5, 1, 0) {
+		t.Errorf("wall was moved to %v", wall.Abs.Pos)
+	}
+	if body.Abs.Pos.X >= 4 {
+		t.Errorf("body was not moved out of the wall: %v", body.Abs.Pos)
+	}
+	if d := hd.Abs.Pos.Sub(body.Abs.Pos); d.Sub(math32.Vec3(0, 0.7, 0)).Length() > 1.0e-5 {
+		t.Errorf("head was not moved along with the body: offset %v", d)
+	}
+}
@@ -17,18 +17,43 @@ type Sphere struct {
 }
 
 func (sp *Sphere) SetBBox() {
-	sp.BBox.SetBounds(math32.Vec3(-sp.Radius, -sp.Radius, -sp.Radius), math32.Vec3(sp.Radius, sp.Radius, sp.Radius))
+	lb := sp.LocalBBox()
+	sp.BBox.SetBounds(lb.Min, lb.Max)
 	sp.BBox.XForm(sp.Abs.Quat, sp.Abs.Pos)
 }
 
+func (sp *Sphere) LocalBBox() math32.Box3 {
+	r := math32.Vector3Scalar(sp.Radius).Mul(sp.Abs.ScaleFactor())
+	return math32.Box3{Min: r.Negate(), Max: r}
+}
+
+func (sp *Sphere) Support(dir math32.Vector3) math32.Vector3 {
+	l := dir.Length()
+	if l == 0 {
+		return math32.Vec3(0, sp.Radius, 0)
+	}
+	return dir.MulScalar(sp.Radius / l)
+}
+
+// SetInertia sets the Rigid.RotInertia for a solid sphere of the current
+// ScaledMass, which is an ellipsoid for non-uniform scales
+func (sp *Sphere) SetInertia() {
+	r := math32.Vector3Scalar(sp.Radius).Mul(sp.Abs.ScaleFactor())
+	sq := r.Mul(r)
+	m := 0.2 * sp.ScaledMass()
+	sp.Rigid.SetShapeInertia(math32.Vec3(m*(sq.Y+sq.Z), m*(sq.X+sq.Z), m*(sq.X+sq.Y)))
+}
+
 func (sp *Sphere) InitAbs(par *NodeBase) {
 	sp.InitAbsBase(par)
+	sp.SetInertia()
 	sp.SetBBox()
 	sp.BBox.VelNilProject()
 }
 
 func (sp *Sphere) RelToAbs(par *NodeBase) {
 	sp.RelToAbsBase(par)
+	sp.SetInertia()
 	sp.SetBBox()
 	sp.BBox.VelProject(sp.Abs.LinVel, 1)
 }
@@ -0,0 +1,185 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package eve
+
+import (
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tree"
+)
+
+// BodyStore is a flat storage backend for the state of the movable bodies
+// in a world (see IsMovable), which mirrors their Abs state, lock factors
+// and bounding boxes into contiguous arrays (struct of arrays), so that
+// integration and bounding box refitting run over the arrays in a
+// cache-friendly way, and keeps the list of all the colliding bodies for
+// the broad phase, so that the tree does not need to be walked through
+// interface calls on each step.  The tree remains the authoring view of
+// the world, and is synced at the boundaries of stepping: Load gathers
+// the state of the bodies from their nodes, and Save writes it back,
+// with the same results as StepPhys on each body.  The Solver uses a
+// BodyStore in its Store when Flat is set.  The store is built from the
+// tree by Sync, and must be rebuilt by calling Invalidate when bodies are
+// added or removed, or change their flags, mass, locks, scale or shape.
+type BodyStore struct {
+
+	// the movable bodies, in tree order
+	Bodies []Body
+
+	// Abs positions of the bodies
+	Pos []math32.Vector3
+
+	// Abs rotations of the bodies
+	Quat []math32.Quat
+
+	// Abs linear velocities of the bodies
+	LinVel []math32.Vector3
+
+	// Abs angular velocities of the bodies
+	AngVel []math32.Vector3
+
+	// linear lock factors of the bodies (see Solver.LockFactors)
+	LinF []math32.Vector3
+
+	// angular lock factors of the bodies (see Solver.LockFactors)
+	AngF []math32.Vector3
+
+	// bounding boxes of the shapes of the bodies in local body coords (see Body.LocalBBox)
+	Local []math32.Box3
+
+	// bounding boxes of the bodies in world coords
+	BBox []BBox
+
+	// nodes of the bodies
+	nodes []*NodeBase
+
+	// all the colliding bodies in the world, for the SpatialHash
+	colliders []hashBody
+
+	// world that the store was built for, nil if not built
+	world *Group
+}
+
+// Invalidate marks the store as needing to be rebuilt by the next Sync,
+// which must be called when bodies are added or removed, or change their
+// flags, mass, locks, scale or shape.
+func (bs *BodyStore) Invalidate() {
+	bs.world = nil
+}
+
+// Len returns the number of movable bodies in the store
+func (bs *BodyStore) Len() int {
+	return len(bs.Bodies)
+}
+
+// Sync builds the store for given world, using the lock factors of given
+// Solver, if it has not already been built for it since the last Invalidate
+func (bs *BodyStore) Sync(world *Group, sv *Solver) {
+	if bs.world == world {
+		return
+	}
+	bs.Build(world, sv)
+}
+
+// Build builds the store from the current state of the bodies in given
+// world, using the lock factors of given Solver.
+func (bs *BodyStore) Build(world *Group, sv *Solver) {
+	bs.world = world
+	bs.colliders = hashBodies(world, bs.colliders[:0])
+	bs.Bodies = bs.Bodies[:0]
+	bs.nodes = bs.nodes[:0]
+	world.WalkDown(func(k tree.Node) bool {
+		nii, ni := AsNode(k)
+		if nii == nil {
+			return false // going into a different type of thing, bail
+		}
+		if !nii.IsDynamic() {
+			return false
+		}
+		if bod := nii.AsBody(); bod != nil && IsMovable(bod) {
+			bs.Bodies = append(bs.Bodies, bod)
+			bs.nodes = append(bs.nodes, ni)
+		}
+		return true
+	})
+	n := len(bs.Bodies)
+	bs.Pos = make([]math32.Vector3, n)
+	bs.Quat = make([]math32.Quat, n)
+	bs.LinVel = make([]math32.Vector3, n)
+	bs.AngVel = make([]math32.Vector3, n)
+	bs.LinF = make([]math32.Vector3, n)
+	bs.AngF = make([]math32.Vector3, n)
+	bs.Local = make([]math32.Box3, n)
+	bs.BBox = make([]BBox, n)
+	for i, bod := range bs.Bodies {
+		bs.LinF[i], bs.AngF[i] = sv.LockFactors(bod.AsBodyBase())
+		bs.Local[i] = bod.LocalBBox()
+		bs.BBox[i] = bs.nodes[i].BBox
+	}
+	bs.Load()
+}
+
+// Load gathers the current Abs state of the bodies from their nodes
+func (bs *BodyStore) Load() {
+	for i, nb := range bs.nodes {
+		bs.Pos[i] = nb.Abs.Pos
+		bs.Quat[i] = nb.Abs.Quat
+		bs.LinVel[i] = nb.Abs.LinVel
+		bs.AngVel[i] = nb.Abs.AngVel
+	}
+}
+
+// Save writes the state of the bodies back to their nodes, updating
+// their Abs state, Rel position and rotation, and bounding boxes,
+// using up to the given number of goroutines.
+func (bs *BodyStore) Save(workers int) {
+	parallelFor(len(bs.nodes), workers, func(i int) {
+		nb := bs.nodes[i]
+		nb.Abs.Pos = bs.Pos[i]
+		nb.Abs.Quat = bs.Quat[i]
+		nb.Abs.LinVel = bs.LinVel[i]
+		nb.Abs.AngVel = bs.AngVel[i]
+		nb.relFromAbs()
+		nb.BBox = bs.BBox[i]
+	})
+}
+
+// Integrate updates the positions and rotations of the bodies from their
+// velocities, subject to their lock factors, as in StepPhys,
+// using up to the given number of goroutines.
+func (bs *BodyStore) Integrate(step float32, workers int) {
+	parallelChunks(len(bs.Pos), workers, func(st, ed int) {
+		for i := st; i < ed; i++ {
+			ps := Phys{Pos: bs.Pos[i], Quat: bs.Quat[i], LinVel: bs.LinVel[i].Mul(bs.LinF[i]), AngVel: bs.AngVel[i].Mul(bs.AngF[i])}
+			ps.StepByAngVel(step)
+			ps.StepByLinVel(step)
+			bs.Pos[i], bs.Quat[i], bs.LinVel[i], bs.AngVel[i] = ps.Pos, ps.Quat, ps.LinVel, ps.AngVel
+		}
+	})
+}
+
+// Refit updates the bounding boxes of the bodies in world coords from
+// their Local bounding boxes and current positions and rotations,
+// projected by their velocities over the step, as in StepPhys,
+// using up to the given number of goroutines.
+func (bs *BodyStore) Refit(step float32, workers int) {
+	parallelChunks(len(bs.BBox), workers, func(st, ed int) {
+		for i := st; i < ed; i++ {
+			bb := &bs.BBox[i]
+			bb.SetBounds(bs.Local[i].Min, bs.Local[i].Max)
+			bb.XForm(bs.Quat[i], bs.Pos[i])
+			bb.VelProject(bs.LinVel[i], step)
+		}
+	})
+}
+
+// Step does one StepPhys update of all the bodies: Load, Integrate, Refit
+// and Save, using up to the given number of goroutines.
+// Group bounding boxes must be updated after, e.g., with WorldDynGroupBBox.
+func (bs *BodyStore) Step(step float32, workers int) {
+	bs.Load()
+	bs.Integrate(step, workers)
+	bs.Refit(step, workers)
+	bs.Save(workers)
+}
@@ -8,12 +8,14 @@ import (
 	"cogentcore.org/core/types"
 )
 
-var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.BBox", IDName: "b-box", Doc: "BBox contains bounding box and other gross object properties", Fields: []types.Field{{Name: "BBox", Doc: "bounding box in world coords (Axis-Aligned Bounding Box = AABB)"}, {Name: "VelBBox", Doc: "velocity-projected bounding box in world coords: extend BBox to include future position of moving bodies -- collision must be made on this basis"}, {Name: "BSphere", Doc: "bounding sphere in local coords"}, {Name: "Area", Doc: "area"}, {Name: "Volume", Doc: "volume"}}})
+var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.Batch", IDName: "batch", Doc: "Batch is a batch of independent worlds that are copies of a Template\nworld, e.g., for training a model on many copies of an environment\nat once.  The Dynamic top-level groups of the Template are cloned for\neach world, while its static top-level groups are cloned only once,\ninto the Static group, which is Shared by all of the worlds, so that\nthe static geometry is not duplicated.  Each world has its own Solver,\nand Step steps all of the worlds concurrently, applying the actions\nfor each world.  Views of a world do not show the Shared static\ngeometry, which can be viewed separately as the Static group.", Fields: []types.Field{{Name: "Template", Doc: "template world that the worlds are copies of"}, {Name: "Static", Doc: "static geometry that is shared by all of the worlds, cloned from the static top-level groups of the Template"}, {Name: "Worlds", Doc: "the worlds, each of which has clones of the Dynamic top-level groups of the Template, with the same names"}, {Name: "Solvers", Doc: "the solver for each world, which are copies of the Solver passed to NewBatch, and can be configured separately, e.g., with the Vehicles and Snaps in each world"}, {Name: "Contacts", Doc: "the contacts from the last Step of each world"}, {Name: "Workers", Doc: "number of goroutines used to step the worlds concurrently -- 0 uses runtime.NumCPU()"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.BBox", IDName: "b-box", Doc: "BBox contains bounding box and other gross object properties", Fields: []types.Field{{Name: "BBox", Doc: "bounding box in world coords (Axis-Aligned Bounding Box = AABB)"}, {Name: "VelBBox", Doc: "velocity-projected bounding box in world coords: extend BBox to include future position of moving bodies -- collision must be made on this basis"}, {Name: "OBB", Doc: "oriented bounding box in world coords, which is tight around rotated bodies -- only for bodies, not groups"}, {Name: "VelOBB", Doc: "velocity-projected oriented bounding box in world coords, used for the midphase of collision detection after the VelBBox test -- only for bodies"}, {Name: "BSphere", Doc: "bounding sphere in world coords"}, {Name: "Area", Doc: "area"}, {Name: "Volume", Doc: "volume"}}})
 
 var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.Body", IDName: "body", Doc: "Body is the common interface for all body types"})
 
 // BodyBaseType is the [types.Type] for [BodyBase]
-var BodyBaseType = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.BodyBase", IDName: "body-base", Doc: "BodyBase is the base type for all specific Body types", Embeds: []types.Field{{Name: "NodeBase"}}, Fields: []types.Field{{Name: "Rigid", Doc: "rigid body properties, including mass, bounce, friction etc"}, {Name: "Vis", Doc: "visualization name -- looks up an entry in the scene library that provides the visual representation of this body"}, {Name: "Color", Doc: "default color of body for basic InitLibrary configuration"}}, Instance: &BodyBase{}})
+var BodyBaseType = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.BodyBase", IDName: "body-base", Doc: "BodyBase is the base type for all specific Body types", Embeds: []types.Field{{Name: "NodeBase"}}, Fields: []types.Field{{Name: "Rigid", Doc: "rigid body properties, including mass, bounce, friction etc"}, {Name: "Vis", Doc: "visualization name -- looks up an entry in the scene library that provides the visual representation of this body -- for a Proxy body, this entry is shown as is, instead of the shape of the body"}, {Name: "Color", Doc: "default color of body for basic InitLibrary configuration"}, {Name: "scriptAngVel", Doc: "rotation of a body that is moved by script over the last\nRelToAbs update, as an angular velocity per step, for use in the\nSolver -- this is kept separate from Abs.AngVel so that StepPhys\ndoes not apply the scripted rotation a second time"}}, Instance: &BodyBase{}})
 
 // NewBodyBase adds a new [BodyBase] with the given name to the given parent:
 // BodyBase is the base type for all specific Body types
@@ -32,7 +34,7 @@ func (t *BodyBase) New() tree.Node { return &BodyBase{} }
 func (t *BodyBase) SetRigid(v Rigid) *BodyBase { t.Rigid = v; return t }
 
 // SetVis sets the [BodyBase.Vis]:
-// visualization name -- looks up an entry in the scene library that provides the visual representation of this body
+// visualization name -- looks up an entry in the scene library that provides the visual representation of this body -- for a Proxy body, this entry is shown as is, instead of the shape of the body
 func (t *BodyBase) SetVis(v string) *BodyBase { t.Vis = v; return t }
 
 // SetColor sets the [BodyBase.Color]:
@@ -45,6 +47,12 @@ func (t *BodyBase) SetInitial(v Phys) *BodyBase { t.Initial = v; return t }
 // SetRel sets the [BodyBase.Rel]
 func (t *BodyBase) SetRel(v Phys) *BodyBase { t.Rel = v; return t }
 
+var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.BoundsModes", IDName: "bounds-modes", Doc: "BoundsModes are the ways that the motion of objects is bounded along\neach axis of a Bounds Region"})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.Bounds", IDName: "bounds", Doc: "Bounds bounds the motion of the objects in a world to a Region, with\na separate BoundsModes for each axis, e.g., wrapping around on the X\nand Z axes for an open field without walls.  The objects are the\nmovable bodies (see IsMovable), and the Dynamic groups and bodies\nthat are moved by script and do not contain any movable bodies\n(e.g., an agent), which are moved as a whole.  Soft bodies and\nParticles are not bounded.  Apply is called by Solver.Step\nafter movable bodies are stepped.\nOnly collisions (SpatialHash) and RayBodyIntersections wrap around:\nthe other queries on the world, such as BodiesInBox, the Overlap\nqueries and NearestBody, only find bodies at their positions within\nthe Region, so they must be repeated with an offset of the Region\nSize to find bodies across the edges.", Fields: []types.Field{{Name: "Region", Doc: "region of the world in world coords"}, {Name: "X", Doc: "how motion is bounded along the X axis"}, {Name: "Y", Doc: "how motion is bounded along the Y axis"}, {Name: "Z", Doc: "how motion is bounded along the Z axis"}, {Name: "Out", Doc: "objects that were outside of the Region on a BoundsReport or BoundsClamp axis on the last Apply"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.hit", IDName: "hit", Fields: []types.Field{{Name: "bp"}, {Name: "dist"}}})
+
 // BoxType is the [types.Type] for [Box]
 var BoxType = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.Box", IDName: "box", Doc: "Box is a box body shape", Embeds: []types.Field{{Name: "BodyBase"}}, Fields: []types.Field{{Name: "Size", Doc: "size of box in each dimension (units arbitrary, as long as they are all consistent -- meters is typical)"}}, Instance: &Box{}})
 
@@ -122,7 +130,9 @@ func (t *Capsule) SetVis(v string) *Capsule { t.Vis = v; return t }
 // SetColor sets the [Capsule.Color]
 func (t *Capsule) SetColor(v string) *Capsule { t.Color = v; return t }
 
-var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.Contact", IDName: "contact", Doc: "Contact is one pairwise point of contact between two bodies.\nContacts are represented in spherical terms relative to the\nspherical BBox of A and B.", Fields: []types.Field{{Name: "A", Doc: "one body"}, {Name: "B", Doc: "the other body"}, {Name: "NormB", Doc: "normal pointing from center of B to center of A"}, {Name: "PtB", Doc: "point on spherical shell of B where A is contacting"}, {Name: "Dist", Doc: "distance from PtB along NormB to contact point on spherical shell of A"}}})
+var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.Character", IDName: "character", Doc: "Character is a kinematic character controller, which moves a scripted\nobject (e.g., an agent) through the world the way that characters move\nin games: a single call to Move resolves any penetrations, slides along\nwalls, steps up over small ledges, and reports whether the character\nis Grounded or Blocked.  The collision shape of the character is the\ngiven Body, typically a Capsule or Box, and the Root node that is moved\ncan be that body, or a Group that contains it along with other bodies\n(e.g., a head with eyes), which are not treated as obstacles.\nThe Root is moved by updating its Rel.Pos, so WorldRelToAbs (or\nSolver.Step) must be called after Move, as usual for scripted updates.\nUp is always the Y axis.  Move does not apply gravity -- include a\ndownward component in the desired motion when not Grounded.\nWhen Grounded on a body that is moved by script (e.g., a Kinematic\nplatform, elevator or turntable), the character is carried along by\nits motion if Ride is set.", Fields: []types.Field{{Name: "World", Doc: "the world containing the character and the obstacles"}, {Name: "Root", Doc: "the node that is moved by the controller: the Body or a Group containing it"}, {Name: "Body", Doc: "the body whose collision shape is used for the character"}, {Name: "StepHeight", Doc: "maximum height of ledges that the character can step up onto"}, {Name: "MaxSlope", Doc: "maximum slope in degrees that the character can walk up -- steeper surfaces are treated as walls"}, {Name: "SkinWidth", Doc: "distance that is maintained between the character and obstacles, to avoid getting stuck due to numerical error"}, {Name: "MaxSlides", Doc: "maximum number of times the motion is deflected along surfaces in each Move"}, {Name: "Ride", Doc: "whether the character is carried along by the scripted motion of the Ground that it is standing on, in position only (its rotation is not changed)"}, {Name: "Grounded", Doc: "true if the character is standing on a walkable surface after the last Move"}, {Name: "Blocked", Doc: "true if the horizontal motion of the last Move was blocked by a wall"}, {Name: "GroundNormal", Doc: "normal of the ground surface when Grounded"}, {Name: "Ground", Doc: "the body that the character is standing on when Grounded"}, {Name: "WallNormal", Doc: "normal of the wall that blocked the character, when Blocked"}, {Name: "obs", Doc: "obstacles near the character, collected at the start of Move"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.Contact", IDName: "contact", Doc: "Contact is one pairwise point of contact between two bodies.\nThe contact geometry is computed by UpdtDist using the narrow-phase\nShapeDist computation on the collision shapes of A and B.", Embeds: []types.Field{{Name: "Surface", Doc: "combined surface properties for this contact, from the Materials of the two bodies"}}, Fields: []types.Field{{Name: "A", Doc: "one body"}, {Name: "B", Doc: "the other body"}, {Name: "NormB", Doc: "normal pointing from B to A, which is the direction to move A to separate it from B"}, {Name: "PtB", Doc: "point on the surface of B closest to A (or deepest within A, if overlapping)"}, {Name: "Dist", Doc: "signed distance from PtB along NormB to the contact point on the surface of A: negative if the bodies overlap, by the penetration depth"}, {Name: "Offset", Doc: "offset of B from its position in the world, for a contact across the edges of a world that wraps around (see Bounds), in which case PtB is also offset"}}})
 
 var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.Contacts", IDName: "contacts", Doc: "Contacts is a slice list of contacts"})
 
@@ -169,8 +179,22 @@ func (t *Cylinder) SetVis(v string) *Cylinder { t.Vis = v; return t }
 // SetColor sets the [Cylinder.Color]
 func (t *Cylinder) SetColor(v string) *Cylinder { t.Color = v; return t }
 
+var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.Fluid", IDName: "fluid", Doc: "Fluid is a region of fluid (e.g., the pool of a water maze), which\napplies buoyancy and drag to the movable bodies that are submerged in it,\nwhen added to Solver.Fluids.  The region is an axis-aligned box in world\ncoords, with the surface of the fluid at the top (Region.Max.Y).\nThe fluid is not visible: add a NoCollide body to show it in the views.", Fields: []types.Field{{Name: "Name", Doc: "name of the fluid, for reference"}, {Name: "Region", Doc: "region occupied by the fluid, in world coords, with the surface at Region.Max.Y"}, {Name: "Density", Doc: "density of the fluid, in units of mass per volume, which determines the buoyancy: bodies float if their average density is less than this -- water is 1000 kg/m^3"}, {Name: "LinDrag", Doc: "linear drag coefficient, as force per unit velocity relative to the fluid, for a fully submerged body"}, {Name: "QuadDrag", Doc: "quadratic drag coefficient, as force per unit squared velocity relative to the fluid, for a fully submerged body"}, {Name: "AngDrag", Doc: "angular drag rate, as the proportion of angular velocity lost per unit time, for a fully submerged body"}, {Name: "Flow", Doc: "velocity of the fluid (e.g., a current), which drag pulls bodies toward"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.shapePose", IDName: "shape-pose", Doc: "shapePose is the collision shape of a body at a given position and\norientation in world coordinates, which may differ from its current\nAbs pose, e.g., for perturbed or swept queries.", Fields: []types.Field{{Name: "bod"}, {Name: "pos"}, {Name: "quat"}, {Name: "scale"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.mkPt", IDName: "mk-pt", Doc: "mkPt is a point in the Minkowski difference of two shapes A - B,\nalong with the support points on A and B that generated it.", Fields: []types.Field{{Name: "A"}, {Name: "B"}, {Name: "W"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.epaFace", IDName: "epa-face", Doc: "epaFace is a triangular face of the expanding polytope", Fields: []types.Field{{Name: "I"}, {Name: "Norm"}, {Name: "Dist"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.edge", IDName: "edge", Fields: []types.Field{{Name: "a"}, {Name: "b"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.GroundHit", IDName: "ground-hit", Doc: "GroundHit is the supporting surface found below a body or point", Fields: []types.Field{{Name: "Body", Doc: "the static body that was hit"}, {Name: "Point", Doc: "point of contact on the surface of the body, in world coords"}, {Name: "Height", Doc: "height of the surface at the point of contact (Point.Y)"}, {Name: "Normal", Doc: "normal of the surface at the point of contact, pointing up"}, {Name: "Slope", Doc: "angle of the surface from horizontal, in degrees"}, {Name: "Dist", Doc: "distance that the body or point moved down before hitting the surface"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.GroundSnap", IDName: "ground-snap", Doc: "GroundSnap keeps a scripted object (e.g., an agent) on the ground, by\nmoving its Root node up or down onto the supporting surface found under\nits Body (see GroundUnder), or under the Root position if Body is nil,\neach time Snap is called.  It can be added to Solver.Snaps so that Snap\nis called at the start of each Solver.Step, after the Rel values have been\nupdated by script.  Surfaces steeper than MaxSlope are not snapped onto,\nand nothing is done if there is no surface within MaxDrop below,\ne.g., when walking off a cliff.", Fields: []types.Field{{Name: "Root", Doc: "the node that is moved: the Body or a Group containing it"}, {Name: "Body", Doc: "the body whose collision shape is placed on the ground -- if nil, the Root position is placed on the ground"}, {Name: "MaxRise", Doc: "maximum distance that the object is moved up onto a higher surface (e.g., up a ramp)"}, {Name: "MaxDrop", Doc: "maximum distance that the object is moved down onto a lower surface"}, {Name: "MaxSlope", Doc: "maximum slope in degrees of surfaces that are snapped onto"}, {Name: "Ground", Doc: "the surface that the object was last snapped onto"}, {Name: "Snapped", Doc: "true if the object was snapped onto the ground on the last call to Snap"}}})
+
 // GroupType is the [types.Type] for [Group]
-var GroupType = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.Group", IDName: "group", Doc: "Group is a container of bodies, joints, or other groups\nit should be used strategically to partition the space\nand its BBox is used to optimize tree-based collision detection.\nUse a group for the top-level World node as well.", Embeds: []types.Field{{Name: "NodeBase"}}, Instance: &Group{}})
+var GroupType = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.Group", IDName: "group", Doc: "Group is a container of bodies, joints, or other groups\nit should be used strategically to partition the space\nand its BBox is used to optimize tree-based collision detection.\nUse a group for the top-level World node as well.", Embeds: []types.Field{{Name: "NodeBase"}}, Fields: []types.Field{{Name: "Shared", Doc: "static geometry that is shared with other worlds (e.g., the worlds of a Batch), which is not part of the tree of this top-level world group, but is included in its collisions and queries as if it were"}}, Instance: &Group{}})
 
 // NewGroup adds a new [Group] with the given name to the given parent:
 // Group is a container of bodies, joints, or other groups
@@ -187,6 +211,10 @@ func (t *Group) NodeType() *types.Type { return GroupType }
 // New returns a new [*Group] value
 func (t *Group) New() tree.Node { return &Group{} }
 
+// SetShared sets the [Group.Shared]:
+// static geometry that is shared with other worlds (e.g., the worlds of a Batch), which is not part of the tree of this top-level world group, but is included in its collisions and queries as if it were
+func (t *Group) SetShared(v *Group) *Group { t.Shared = v; return t }
+
 // SetInitial sets the [Group.Initial]
 func (t *Group) SetInitial(v Phys) *Group { t.Initial = v; return t }
 
@@ -195,15 +223,72 @@ func (t *Group) SetRel(v Phys) *Group { t.Rel = v; return t }
 
 var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.BodyPoint", IDName: "body-point", Doc: "BodyPoint contains a Body and a Point on that body", Fields: []types.Field{{Name: "Body"}, {Name: "Point"}}})
 
+// HullType is the [types.Type] for [Hull]
+var HullType = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.Hull", IDName: "hull", Doc: "Hull is a convex hull body shape, defined by a set of points in local\ncoordinates relative to its center of mass, which is mainly useful as a\ncollision Proxy that closely fits a detailed visual model.\nThe points do not need to be on the hull: interior points are ignored.", Embeds: []types.Field{{Name: "BodyBase"}}, Fields: []types.Field{{Name: "Points", Doc: "points in local coordinates whose convex hull is the shape"}}, Instance: &Hull{}})
+
+// NewHull adds a new [Hull] with the given name to the given parent:
+// Hull is a convex hull body shape, defined by a set of points in local
+// coordinates relative to its center of mass, which is mainly useful as a
+// collision Proxy that closely fits a detailed visual model.
+// The points do not need to be on the hull: interior points are ignored.
+func NewHull(parent tree.Node, name ...string) *Hull {
+	return parent.NewChild(HullType, name...).(*Hull)
+}
+
+// NodeType returns the [*types.Type] of [Hull]
+func (t *Hull) NodeType() *types.Type { return HullType }
+
+// New returns a new [*Hull] value
+func (t *Hull) New() tree.Node { return &Hull{} }
+
+// SetPoints sets the [Hull.Points]:
+// points in local coordinates whose convex hull is the shape
+func (t *Hull) SetPoints(v ...math32.Vector3) *Hull { t.Points = v; return t }
+
+// SetInitial sets the [Hull.Initial]
+func (t *Hull) SetInitial(v Phys) *Hull { t.Initial = v; return t }
+
+// SetRel sets the [Hull.Rel]
+func (t *Hull) SetRel(v Phys) *Hull { t.Rel = v; return t }
+
+// SetRigid sets the [Hull.Rigid]
+func (t *Hull) SetRigid(v Rigid) *Hull { t.Rigid = v; return t }
+
+// SetVis sets the [Hull.Vis]
+func (t *Hull) SetVis(v string) *Hull { t.Vis = v; return t }
+
+// SetColor sets the [Hull.Color]
+func (t *Hull) SetColor(v string) *Hull { t.Color = v; return t }
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.IKLink", IDName: "ik-link", Doc: "IKLink is one link in an IK chain, which is rotated about its own\norigin by changing its Rel.Quat", Fields: []types.Field{{Name: "Node", Doc: "the node that is rotated: a Group or Body in the chain"}, {Name: "MaxAngle", Doc: "maximum rotation in degrees away from the Rest rotation -- 0 = unlimited"}, {Name: "Axis", Doc: "if nonzero, the link only rotates about this axis in its own local coordinates, as a hinge (e.g., an elbow) -- otherwise it rotates freely, as a ball joint"}, {Name: "Fixed", Doc: "if true, the link is not rotated, e.g., for an intermediate group in the chain"}, {Name: "Rest", Doc: "the rest rotation that MaxAngle is relative to, from the Rel.Quat when the chain was made"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.IK", IDName: "ik", Doc: "IK is an inverse kinematics solver for a chain of nested nodes (Groups\nor Bodies) in the scripted style of updating, which adjusts the Rel.Quat\nof each link so that the Tip of the End node reaches a target position\n(Reach), or its Aim axis points toward a target (LookAt), e.g., for a\nhead and eyes that look at an object, or an arm that reaches toward it.\nIt uses cyclic coordinate descent (CCD), rotating each link in turn,\nfrom the End toward the root, to bring the Tip as close as possible to\nthe target, within the MaxAngle limits and hinge Axis of each link.\nAs usual for scripted updates, the nodes in the chain must be Dynamic\n(e.g., groups that contain Dynamic bodies) for their Abs poses to be\nupdated by WorldRelToAbs.", Fields: []types.Field{{Name: "Links", Doc: "the links of the chain, from the root to the End node, in order of nesting"}, {Name: "End", Doc: "the end node of the chain (e.g., a hand or head), which is also the last of the Links"}, {Name: "Tip", Doc: "the point in the local coordinates of the End node that reaches the target (e.g., the tip of a finger)"}, {Name: "Aim", Doc: "the axis in the local coordinates of the End node that is pointed at the target by LookAt"}, {Name: "Iters", Doc: "maximum number of iterations over the chain"}, {Name: "Tol", Doc: "distance from the target within which the solution is done"}, {Name: "World", Doc: "if non-nil, WorldRelToAbs is called on this world after solving -- leave nil when Solver.Step applies the updated Rel values"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.BodyPair", IDName: "body-pair", Doc: "BodyPair is the key for the contact Manifold between two bodies,\nincluding the Offset of B, so that the contacts between the same\nbodies across the seams of a wrap-around world (see Bounds) each\nhave their own Manifold.", Fields: []types.Field{{Name: "A"}, {Name: "B"}, {Name: "Offset", Doc: "offset of B from its position in the world, from Contact.Offset"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.ManifoldPoint", IDName: "manifold-point", Doc: "ManifoldPoint is one point of contact in a Manifold, which persists\nacross steps as long as the bodies remain in contact at that point,\nalong with the impulses applied there on the last step, which are\nused to warm start the Solver on the next step.", Fields: []types.Field{{Name: "LocalA", Doc: "contact point on A, in the local coordinates of A"}, {Name: "LocalB", Doc: "contact point on B, in the local coordinates of B"}, {Name: "LocalNormB", Doc: "normal pointing from B to A, in the local coordinates of B"}, {Name: "PtA", Doc: "contact point on A in world coordinates"}, {Name: "PtB", Doc: "contact point on B in world coordinates"}, {Name: "NormB", Doc: "normal pointing from B to A in world coordinates"}, {Name: "Dist", Doc: "signed distance between PtB and PtA along NormB: negative if overlapping"}, {Name: "FeatureA", Doc: "identifies the feature of the shape of A at the contact point (e.g., a box vertex), or 0 if none (see ShapeFeature)"}, {Name: "FeatureB", Doc: "identifies the feature of the shape of B at the contact point, or 0 if none"}, {Name: "ImpN", Doc: "accumulated normal impulse applied on the last step"}, {Name: "ImpT", Doc: "accumulated friction impulse applied on the last step, in world coordinates"}, {Name: "ImpRoll", Doc: "accumulated rolling friction angular impulse applied on the last step"}, {Name: "ImpSpin", Doc: "accumulated spinning friction angular impulse applied on the last step"}, {Name: "Age", Doc: "number of steps that this point has persisted"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.Manifold", IDName: "manifold", Doc: "Manifold is the persistent set of contact points between two bodies,\nwhich is updated on each step from the narrow-phase ShapeDist contact,\nkeeping up to MaxManifoldPoints points that cover the largest area.\nPoints that were generated on previous steps are moved along with the\nbodies, and are dropped when the bodies separate or slide apart there.", Fields: []types.Field{{Name: "A", Doc: "one body"}, {Name: "B", Doc: "the other body"}, {Name: "Offset", Doc: "offset of B from its position in the world, from Contact.Offset"}, {Name: "Points", Doc: "current contact points"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.Surface", IDName: "surface", Doc: "Surface has the properties of a surface that determine the response\nto contact with another surface, for a single material or for the\ncombination of two materials in contact.", Fields: []types.Field{{Name: "Friction", Doc: "dynamic friction coefficient -- how much friction is generated by transverse (sliding) motion"}, {Name: "StaticFriction", Doc: "static friction coefficient -- how much transverse force can be resisted without sliding -- if 0, Friction is used"}, {Name: "Bounce", Doc: "COR or coefficient of restitution -- how elastic is the collision i.e., final velocity / initial velocity"}, {Name: "RollFriction", Doc: "rolling friction coefficient, in units of length (the lever arm of the resisting torque relative to the normal force) -- resists rolling, mainly relevant for spheres and cylinders"}, {Name: "SpinFriction", Doc: "spinning friction coefficient, in units of length -- resists spinning about the contact normal"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.Material", IDName: "material", Doc: "Material specifies the surface properties of a named material,\nsuch as ice, rubber or wood, which bodies can reference by name\nvia Rigid.Material.", Embeds: []types.Field{{Name: "Surface", Doc: "surface properties of the material"}}, Fields: []types.Field{{Name: "Name", Doc: "name of the material -- used as the key in the Materials table"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.Materials", IDName: "materials", Doc: "Materials is a table of named materials, with optional pairwise\noverrides, and the rules for combining the surface properties\nof two bodies that come into contact.", Fields: []types.Field{{Name: "FrictionCombine", Doc: "rule for combining dynamic and static friction values of two bodies"}, {Name: "RollCombine", Doc: "rule for combining rolling and spinning friction values of two bodies, which are in units of length, so that multiplying them would not make sense"}, {Name: "BounceCombine", Doc: "rule for combining bounce values of two bodies"}, {Name: "Mats", Doc: "materials by name"}, {Name: "Pairs", Doc: "pairwise overrides, keyed by material names -- use SetPair to add, which ensures that the order of names does not matter"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.MaterialKey", IDName: "material-key", Doc: "MaterialKey is the key for a pair of materials, with names in sorted order", Fields: []types.Field{{Name: "A"}, {Name: "B"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.CombineModes", IDName: "combine-modes", Doc: "CombineModes are the ways of combining a material property from two bodies in contact"})
+
 var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.Node", IDName: "node", Doc: "Node is the common interface for all eve nodes"})
 
 // NodeBaseType is the [types.Type] for [NodeBase]
-var NodeBaseType = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.NodeBase", IDName: "node-base", Doc: "NodeBase is the basic eve node, which has position, rotation, velocity\nand computed bounding boxes, etc.\nThere are only three different kinds of Nodes: Group, Body, and Joint", Embeds: []types.Field{{Name: "NodeBase"}}, Fields: []types.Field{{Name: "Initial", Doc: "initial position, orientation, velocity in *local* coordinates (relative to parent)"}, {Name: "Rel", Doc: "current relative (local) position, orientation, velocity -- only change these values, as abs values are computed therefrom"}, {Name: "Abs", Doc: "current absolute (world) position, orientation, velocity"}, {Name: "BBox", Doc: "bounding box in world coordinates (aggregated for groups)"}}, Instance: &NodeBase{}})
+var NodeBaseType = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.NodeBase", IDName: "node-base", Doc: "NodeBase is the basic eve node, which has position, rotation, velocity\nand computed bounding boxes, etc.\nThere are only five different kinds of Nodes: Group, Body, Joint, Soft, and Particles", Embeds: []types.Field{{Name: "NodeBase"}}, Fields: []types.Field{{Name: "Initial", Doc: "initial position, orientation, velocity in *local* coordinates (relative to parent)"}, {Name: "Rel", Doc: "current relative (local) position, orientation, velocity -- only change these values, as abs values are computed therefrom"}, {Name: "Abs", Doc: "current absolute (world) position, orientation, velocity"}, {Name: "BBox", Doc: "bounding box in world coordinates (aggregated for groups)"}}, Instance: &NodeBase{}})
 
 // NewNodeBase adds a new [NodeBase] with the given name to the given parent:
 // NodeBase is the basic eve node, which has position, rotation, velocity
 // and computed bounding boxes, etc.
-// There are only three different kinds of Nodes: Group, Body, and Joint
+// There are only five different kinds of Nodes: Group, Body, Joint, Soft, and Particles
 func NewNodeBase(parent tree.Node, name ...string) *NodeBase {
 	return parent.NewChild(NodeBaseType, name...).(*NodeBase)
 }
@@ -226,9 +311,203 @@ var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.NodeTypes",
 
 var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.NodeFlags", IDName: "node-flags", Doc: "NodeFlags define eve node bitflags -- uses ki Flags field (64 bit capacity)"})
 
-var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.Phys", IDName: "phys", Doc: "Phys contains the basic physical properties including position, orientation, velocity.\nThese are only the values that can be either relative or absolute -- other physical\nstate values such as Mass should go in Rigid.", Fields: []types.Field{{Name: "Pos", Doc: "position of center of mass of object"}, {Name: "Quat", Doc: "rotation specified as a Quat"}, {Name: "LinVel", Doc: "linear velocity"}, {Name: "AngVel", Doc: "angular velocity"}}})
+var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.OBB", IDName: "obb", Doc: "OBB is an oriented bounding box in world coords, which remains tight\naround a rotated body, unlike the axis-aligned BBox, which can be much\nlarger than the body (e.g., for a long wall rotated 45 degrees).", Fields: []types.Field{{Name: "Center", Doc: "center of the box in world coords"}, {Name: "HalfSize", Doc: "half of the size of the box along each of its local axes"}, {Name: "Quat", Doc: "orientation of the box in world coords"}}})
+
+// ParticlesType is the [types.Type] for [Particles]
+var ParticlesType = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.Particles", IDName: "particles", Doc: "Particles is a set of many small spheres (e.g., sand, falling food\npellets, or the puffs of an odor or smoke plume), which are stored and\nupdated in bulk, rather than as individual Body nodes in the tree.\nEach particle moves under gravity (scaled by GravityScale, e.g., negative\nfor rising smoke), is slowed by Drag, bounces off of and slides along the\nstatic (non-Dynamic) bodies in the world if Collide is set, and is removed\nwhen it has reached the end of its life.  If Granular is set, particles\nalso collide with each other, so that they pile up (e.g., sand).\nParticles are added by Emit, or by the Emitters, and are updated by\nStep, which the Solver calls for all Particles in the world.\nThe positions of the particles are in world coordinates, and all\nparticles are removed by InitAbs.", Embeds: []types.Field{{Name: "NodeBase"}}, Fields: []types.Field{{Name: "Radius", Doc: "radius of each particle"}, {Name: "Max", Doc: "maximum number of particles: no more are emitted while there are this many"}, {Name: "GravityScale", Doc: "multiplier on gravity for the particles, e.g., 0 for particles that drift, or negative for particles that rise"}, {Name: "Drag", Doc: "proportion of the velocity of the particles that is lost per unit time, e.g., from air resistance"}, {Name: "Collide", Doc: "whether particles collide with the static bodies in the world"}, {Name: "Granular", Doc: "whether particles collide with each other, so that they pile up"}, {Name: "Bounce", Doc: "coefficient of restitution for collisions: 0 = no bounce, 1 = full bounce"}, {Name: "Friction", Doc: "coefficient of friction for collisions"}, {Name: "Iters", Doc: "number of iterations over all the collisions per step -- more iterations give more accurate results for piles of Granular particles"}, {Name: "Emitters", Doc: "sources that emit particles on each Step"}, {Name: "Seed", Doc: "seed for the random numbers used by the Emitters, for reproducible results"}, {Name: "Color", Doc: "color to draw in, as a standard color name or hex value"}, {Name: "Pos", Doc: "current positions of the particles in world coordinates"}, {Name: "Vel", Doc: "current velocities of the particles in world coordinates"}, {Name: "Age", Doc: "time since each particle was emitted"}, {Name: "Life", Doc: "lifetime of each particle, after which it is removed: 0 = forever"}, {Name: "rand", Doc: "random number generator for the Emitters"}}, Instance: &Particles{}})
+
+// NewParticles adds a new [Particles] with the given name to the given parent:
+// Particles is a set of many small spheres (e.g., sand, falling food
+// pellets, or the puffs of an odor or smoke plume), which are stored and
+// updated in bulk, rather than as individual Body nodes in the tree.
+// Each particle moves under gravity (scaled by GravityScale, e.g., negative
+// for rising smoke), is slowed by Drag, bounces off of and slides along the
+// static (non-Dynamic) bodies in the world if Collide is set, and is removed
+// when it has reached the end of its life.  If Granular is set, particles
+// also collide with each other, so that they pile up (e.g., sand).
+// Particles are added by Emit, or by the Emitters, and are updated by
+// Step, which the Solver calls for all Particles in the world.
+// The positions of the particles are in world coordinates, and all
+// particles are removed by InitAbs.
+func NewParticles(parent tree.Node, name ...string) *Particles {
+	return parent.NewChild(ParticlesType, name...).(*Particles)
+}
+
+// NodeType returns the [*types.Type] of [Particles]
+func (t *Particles) NodeType() *types.Type { return ParticlesType }
+
+// New returns a new [*Particles] value
+func (t *Particles) New() tree.Node { return &Particles{} }
+
+// SetRadius sets the [Particles.Radius]:
+// radius of each particle
+func (t *Particles) SetRadius(v float32) *Particles { t.Radius = v; return t }
+
+// SetMax sets the [Particles.Max]:
+// maximum number of particles: no more are emitted while there are this many
+func (t *Particles) SetMax(v int) *Particles { t.Max = v; return t }
+
+// SetGravityScale sets the [Particles.GravityScale]:
+// multiplier on gravity for the particles, e.g., 0 for particles that drift, or negative for particles that rise
+func (t *Particles) SetGravityScale(v float32) *Particles { t.GravityScale = v; return t }
+
+// SetDrag sets the [Particles.Drag]:
+// proportion of the velocity of the particles that is lost per unit time, e.g., from air resistance
+func (t *Particles) SetDrag(v float32) *Particles { t.Drag = v; return t }
+
+// SetCollide sets the [Particles.Collide]:
+// whether particles collide with the static bodies in the world
+func (t *Particles) SetCollide(v bool) *Particles { t.Collide = v; return t }
+
+// SetGranular sets the [Particles.Granular]:
+// whether particles collide with each other, so that they pile up
+func (t *Particles) SetGranular(v bool) *Particles { t.Granular = v; return t }
+
+// SetBounce sets the [Particles.Bounce]:
+// coefficient of restitution for collisions: 0 = no bounce, 1 = full bounce
+func (t *Particles) SetBounce(v float32) *Particles { t.Bounce = v; return t }
+
+// SetFriction sets the [Particles.Friction]:
+// coefficient of friction for collisions
+func (t *Particles) SetFriction(v float32) *Particles { t.Friction = v; return t }
+
+// SetIters sets the [Particles.Iters]:
+// number of iterations over all the collisions per step -- more iterations give more accurate results for piles of Granular particles
+func (t *Particles) SetIters(v int) *Particles { t.Iters = v; return t }
+
+// SetEmitters sets the [Particles.Emitters]:
+// sources that emit particles on each Step
+func (t *Particles) SetEmitters(v ...*Emitter) *Particles { t.Emitters = v; return t }
 
-var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.Rigid", IDName: "rigid", Doc: "Rigid contains the full specification of a given object's basic physics\nproperties including position, orientation, velocity.  These", Fields: []types.Field{{Name: "InvMass", Doc: "1/mass -- 0 for no mass"}, {Name: "Bounce", Doc: "COR or coefficient of restitution -- how elastic is the collision i.e., final velocity / initial velocity"}, {Name: "Friction", Doc: "friction coefficient -- how much friction is generated by transverse motion"}, {Name: "Force", Doc: "record of computed force vector from last iteration"}, {Name: "RotInertia", Doc: "Last calculated rotational inertia matrix in local coords"}}})
+// SetSeed sets the [Particles.Seed]:
+// seed for the random numbers used by the Emitters, for reproducible results
+func (t *Particles) SetSeed(v int64) *Particles { t.Seed = v; return t }
+
+// SetColor sets the [Particles.Color]:
+// color to draw in, as a standard color name or hex value
+func (t *Particles) SetColor(v string) *Particles { t.Color = v; return t }
+
+// SetInitial sets the [Particles.Initial]
+func (t *Particles) SetInitial(v Phys) *Particles { t.Initial = v; return t }
+
+// SetRel sets the [Particles.Rel]
+func (t *Particles) SetRel(v Phys) *Particles { t.Rel = v; return t }
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.Emitter", IDName: "emitter", Doc: "Emitter is a source of Particles, which emits them at a given Rate\nfrom a region around a point, in a cone of directions", Fields: []types.Field{{Name: "On", Doc: "whether the emitter is emitting particles"}, {Name: "Body", Doc: "body that the emitter is attached to (e.g., the source of an odor), or nil for a fixed position in the world"}, {Name: "Pos", Doc: "position of the emitter, in the local coordinates of the Body, or in world coordinates if the Body is nil"}, {Name: "Extent", Doc: "half-size of the box around Pos in world coordinates that particles are emitted from, e.g., to pour sand from an area"}, {Name: "Dir", Doc: "direction in world coordinates that particles are emitted in"}, {Name: "Speed", Doc: "speed that particles are emitted with"}, {Name: "Spread", Doc: "angle in degrees of the cone of directions around Dir that particles are emitted in"}, {Name: "Rate", Doc: "number of particles emitted per unit time"}, {Name: "Life", Doc: "lifetime of the emitted particles: 0 = forever"}, {Name: "accum", Doc: "fractional number of particles that remain to be emitted"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.particleCell", IDName: "particle-cell", Doc: "particleCell is the key of a cell in the grid used for collisions\nbetween particles", Fields: []types.Field{{Name: "X"}, {Name: "Y"}, {Name: "Z"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.Phys", IDName: "phys", Doc: "Phys contains the basic physical properties including position, orientation, velocity.\nThese are only the values that can be either relative or absolute -- other physical\nstate values such as Mass should go in Rigid.", Fields: []types.Field{{Name: "Pos", Doc: "position of center of mass of object"}, {Name: "Quat", Doc: "rotation specified as a Quat"}, {Name: "Scale", Doc: "scale factors along the local X, Y, Z axes, which multiply the size of body shapes and the positions of child nodes -- zero is treated as 1 (unscaled).  Non-uniform scales of a parent are only represented exactly for children that are rotated by multiples of 90 degrees relative to it, as they would otherwise be sheared."}, {Name: "LinVel", Doc: "linear velocity"}, {Name: "AngVel", Doc: "angular velocity"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.Rigid", IDName: "rigid", Doc: "Rigid contains the full specification of a given object's basic physics\nproperties including position, orientation, velocity.  These", Fields: []types.Field{{Name: "InvMass", Doc: "1/mass -- 0 for no mass -- this is the mass of the unscaled shape, which scales with its volume (see BodyBase.ScaledMass)"}, {Name: "Bounce", Doc: "COR or coefficient of restitution -- how elastic is the collision i.e., final velocity / initial velocity"}, {Name: "Friction", Doc: "friction coefficient -- how much friction is generated by transverse motion"}, {Name: "StaticFriction", Doc: "static friction coefficient -- how much transverse force can be resisted without sliding -- if 0, Friction is used"}, {Name: "RollFriction", Doc: "rolling friction coefficient, in units of length -- resists rolling, mainly relevant for spheres and cylinders"}, {Name: "SpinFriction", Doc: "spinning friction coefficient, in units of length -- resists spinning about the contact normal"}, {Name: "Material", Doc: "name of the surface material, which is looked up in the Materials table of the Solver to determine the surface properties -- if empty or not found, the Friction, Bounce etc values here are used"}, {Name: "FrictionDir", Doc: "direction in local body coordinates for anisotropic friction (e.g., the blade of a skate or the rolling direction of a wheel) -- if zero, friction is isotropic"}, {Name: "FrictionDirScale", Doc: "factor multiplying friction along FrictionDir, e.g., a small value for a skate blade that slides easily forward but not sideways -- if 0, 1 is used"}, {Name: "SurfaceVel", Doc: "velocity of the surface of the body relative to the body itself, in local body coordinates, e.g., the belt of a conveyor, which carries bodies that rest on it along by friction, while the body itself stays put"}, {Name: "LinLock", Doc: "per-axis locking of linear motion along the X, Y, Z world axes in the Solver: 0 = free, 1 = fully locked, with intermediate values scaling the response to impulses and gravity"}, {Name: "AngLock", Doc: "per-axis locking of angular motion (rotation about the X, Y, Z world axes) in the Solver: 0 = free, 1 = fully locked, with intermediate values scaling the response to impulses"}, {Name: "Force", Doc: "record of computed force vector from last iteration"}, {Name: "RotInertia", Doc: "rotational inertia matrix in local coords -- if left at zero, it is computed from the shape and mass of the body, otherwise the value set here is used as-is"}, {Name: "autoInertia", Doc: "true if RotInertia was computed from the shape, so it is kept up to date with changes in shape and mass"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.Sight", IDName: "sight", Doc: "Sight determines the ground-truth visibility of bodies from the viewpoint\nof an Eye node, e.g., for attention, social gaze or hide-and-seek tasks,\nwithout rendering an image.  The Eye looks along its -Z axis with Y up,\nas for a camera (see evev.View.RenderOffNode), within a field of view and\nmaximum distance, and lines of sight are blocked by the collision shapes\nof the occluding bodies.  Visible samples lines of sight to points\nspread over the outline of the target body as seen from the Eye,\nand returns the fraction of them that reach it.", Fields: []types.Field{{Name: "Eye", Doc: "node whose Abs pose is the viewpoint, typically an eye body or group of an agent"}, {Name: "FOV", Doc: "horizontal field of view in degrees -- 0 or 360 sees all around"}, {Name: "VFOV", Doc: "vertical field of view in degrees -- 0 uses FOV"}, {Name: "MaxDist", Doc: "maximum distance from the Eye to a visible point"}, {Name: "Samples", Doc: "number of lines of sight to points on the target: the first is to its center, and the rest are spread over its outline as seen from the Eye"}, {Name: "Skip", Doc: "subtree of the world whose bodies do not block the view (e.g., the agent that the Eye belongs to)"}, {Name: "Occluders", Doc: "if non-nil, only these bodies block the view -- otherwise all colliding bodies in the world do (see Collides), except for those in Skip"}}})
+
+// SoftType is the [types.Type] for [Soft]
+var SoftType = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.Soft", IDName: "soft", Doc: "Soft is a deformable body (e.g., a rope, string, curtain or flexible\nbarrier), made of points connected by distance constraints (Links),\nwhich is simulated by the Solver using position-based dynamics:\nthe points move under gravity, the constraints are enforced by directly\ncorrecting their positions, and they are pushed out of the collision\nshapes of the bodies in the world.  Points can be attached to bodies\nwith Pins, which pull on movable bodies in turn (e.g., pulling a string\nthat is tied to an object).  The current positions of the points are in\nworld coordinates (Pos), and are initialized by InitAbs from the Points\nin local coordinates: changing the Rel pose afterwards has no effect.", Embeds: []types.Field{{Name: "NodeBase"}}, Fields: []types.Field{{Name: "Points", Doc: "positions of the points in local coordinates relative to this node, which determine their initial world positions, and the rest lengths of the Links and Bends"}, {Name: "Mass", Doc: "total mass of the points, which is divided evenly among them"}, {Name: "Links", Doc: "distance constraints between pairs of points that hold the structure together (e.g., the segments of a rope, and the edges and diagonals of cloth), which are drawn as lines if there are no Tris"}, {Name: "Bends", Doc: "softer distance constraints between pairs of points that resist bending (e.g., between every other point along a rope)"}, {Name: "Tris", Doc: "triangles as triples of point indexes, which are drawn as a surface (e.g., for cloth)"}, {Name: "Pins", Doc: "attachments of points to bodies, or to fixed positions in the world"}, {Name: "Radius", Doc: "radius of each point for collisions with bodies, which is also the thickness of drawn lines"}, {Name: "Stiff", Doc: "proportion of the stretch of the Links that is corrected per iteration (0-1)"}, {Name: "BendStiff", Doc: "proportion of the stretch of the Bends that is corrected per iteration (0-1)"}, {Name: "Iters", Doc: "number of iterations over all the constraints per step -- more iterations make the Links less stretchy"}, {Name: "Damping", Doc: "proportion of the velocity of the points that is lost per unit time, e.g., from air resistance"}, {Name: "Friction", Doc: "proportion of the motion of points along the surface of a body that is lost in contact with it"}, {Name: "Color", Doc: "color to draw in, as a standard color name or hex value"}, {Name: "Pos", Doc: "current positions of the points in world coordinates"}, {Name: "Vel", Doc: "current velocities of the points in world coordinates"}, {Name: "linkLen", Doc: "rest lengths of the Links and Bends, from the initial positions"}, {Name: "bendLen", Doc: "rest lengths of the Links and Bends, from the initial positions"}, {Name: "pinOf", Doc: "index into Pins for each point, or -1 if free"}}, Instance: &Soft{}})
+
+// NewSoft adds a new [Soft] with the given name to the given parent:
+// Soft is a deformable body (e.g., a rope, string, curtain or flexible
+// barrier), made of points connected by distance constraints (Links),
+// which is simulated by the Solver using position-based dynamics:
+// the points move under gravity, the constraints are enforced by directly
+// correcting their positions, and they are pushed out of the collision
+// shapes of the bodies in the world.  Points can be attached to bodies
+// with Pins, which pull on movable bodies in turn (e.g., pulling a string
+// that is tied to an object).  The current positions of the points are in
+// world coordinates (Pos), and are initialized by InitAbs from the Points
+// in local coordinates: changing the Rel pose afterwards has no effect.
+func NewSoft(parent tree.Node, name ...string) *Soft {
+	return parent.NewChild(SoftType, name...).(*Soft)
+}
+
+// NodeType returns the [*types.Type] of [Soft]
+func (t *Soft) NodeType() *types.Type { return SoftType }
+
+// New returns a new [*Soft] value
+func (t *Soft) New() tree.Node { return &Soft{} }
+
+// SetPoints sets the [Soft.Points]:
+// positions of the points in local coordinates relative to this node, which determine their initial world positions, and the rest lengths of the Links and Bends
+func (t *Soft) SetPoints(v ...math32.Vector3) *Soft { t.Points = v; return t }
+
+// SetMass sets the [Soft.Mass]:
+// total mass of the points, which is divided evenly among them
+func (t *Soft) SetMass(v float32) *Soft { t.Mass = v; return t }
+
+// SetLinks sets the [Soft.Links]:
+// distance constraints between pairs of points that hold the structure together (e.g., the segments of a rope, and the edges and diagonals of cloth), which are drawn as lines if there are no Tris
+func (t *Soft) SetLinks(v ...SoftLink) *Soft { t.Links = v; return t }
+
+// SetBends sets the [Soft.Bends]:
+// softer distance constraints between pairs of points that resist bending (e.g., between every other point along a rope)
+func (t *Soft) SetBends(v ...SoftLink) *Soft { t.Bends = v; return t }
+
+// SetTris sets the [Soft.Tris]:
+// triangles as triples of point indexes, which are drawn as a surface (e.g., for cloth)
+func (t *Soft) SetTris(v ...int) *Soft { t.Tris = v; return t }
+
+// SetPins sets the [Soft.Pins]:
+// attachments of points to bodies, or to fixed positions in the world
+func (t *Soft) SetPins(v ...SoftPin) *Soft { t.Pins = v; return t }
+
+// SetRadius sets the [Soft.Radius]:
+// radius of each point for collisions with bodies, which is also the thickness of drawn lines
+func (t *Soft) SetRadius(v float32) *Soft { t.Radius = v; return t }
+
+// SetStiff sets the [Soft.Stiff]:
+// proportion of the stretch of the Links that is corrected per iteration (0-1)
+func (t *Soft) SetStiff(v float32) *Soft { t.Stiff = v; return t }
+
+// SetBendStiff sets the [Soft.BendStiff]:
+// proportion of the stretch of the Bends that is corrected per iteration (0-1)
+func (t *Soft) SetBendStiff(v float32) *Soft { t.BendStiff = v; return t }
+
+// SetIters sets the [Soft.Iters]:
+// number of iterations over all the constraints per step -- more iterations make the Links less stretchy
+func (t *Soft) SetIters(v int) *Soft { t.Iters = v; return t }
+
+// SetDamping sets the [Soft.Damping]:
+// proportion of the velocity of the points that is lost per unit time, e.g., from air resistance
+func (t *Soft) SetDamping(v float32) *Soft { t.Damping = v; return t }
+
+// SetFriction sets the [Soft.Friction]:
+// proportion of the motion of points along the surface of a body that is lost in contact with it
+func (t *Soft) SetFriction(v float32) *Soft { t.Friction = v; return t }
+
+// SetColor sets the [Soft.Color]:
+// color to draw in, as a standard color name or hex value
+func (t *Soft) SetColor(v string) *Soft { t.Color = v; return t }
+
+// SetInitial sets the [Soft.Initial]
+func (t *Soft) SetInitial(v Phys) *Soft { t.Initial = v; return t }
+
+// SetRel sets the [Soft.Rel]
+func (t *Soft) SetRel(v Phys) *Soft { t.Rel = v; return t }
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.SoftLink", IDName: "soft-link", Doc: "SoftLink is a distance constraint between two points of a Soft body,\nwhich keeps them at their initial distance", Fields: []types.Field{{Name: "A", Doc: "index of the first point"}, {Name: "B", Doc: "index of the second point"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.SoftPin", IDName: "soft-pin", Doc: "SoftPin attaches a point of a Soft body to a body, or to a fixed position", Fields: []types.Field{{Name: "Point", Doc: "index of the point"}, {Name: "Body", Doc: "body that the point is attached to -- if nil, the point is fixed at its initial position in the world"}, {Name: "Local", Doc: "position of the attachment in the local coordinates of the Body, or in world coordinates if the Body is nil, which is set by InitAbs to the initial position of the point"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.softPin", IDName: "soft-pin", Doc: "softPin is the working state of a Pin during a step", Fields: []types.Field{{Name: "pin"}, {Name: "sb", Doc: "working state of the body, if it is movable, else nil"}, {Name: "r", Doc: "attachment point relative to the body center, in world coords"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.Solver", IDName: "solver", Doc: "Solver resolves contacts between bodies for the Physics updating mode,\nby applying impulses to the Abs.LinVel and Abs.AngVel of movable bodies\n(see IsMovable), so that they bounce off of, slide and roll along each\nother according to the combined Surface properties of their Materials.\nKinematic bodies, and any other Dynamic bodies without mass that are\nmoved by script (e.g., the contents of a moving Group), have infinite\nmass, and push and carry movable bodies according to the velocity of\ntheir scripted motion, so that bodies resting on moving platforms,\nelevators and turntables move along with them through friction.", Fields: []types.Field{{Name: "Materials", Doc: "table of named materials, and rules for combining the surface properties of two bodies in contact"}, {Name: "Gravity", Doc: "acceleration due to gravity, which is added to the velocity of movable bodies in Step"}, {Name: "Planar", Doc: "constrains all dynamics to a plane, e.g., PlanarXZ for top-down navigation, eliminating drift and tipping over out of the plane -- this is combined with the per-body Rigid.LinLock and AngLock"}, {Name: "Iters", Doc: "number of iterations over all contacts per step -- more iterations give more accurate results for stacks of bodies"}, {Name: "Slop", Doc: "penetration depth that is allowed without correction, to avoid jitter for resting contacts"}, {Name: "Bias", Doc: "proportion of the penetration beyond Slop that is corrected per step"}, {Name: "BounceThr", Doc: "contacts with an approach velocity below this threshold do not bounce, so that bodies can come to rest"}, {Name: "ContactBreak", Doc: "distance beyond which points in the persistent contact Manifolds are dropped, and within which new points replace existing ones"}, {Name: "WarmStart", Doc: "proportion of the impulses from the last step that are applied at the start of the current step for persistent contact points, which greatly speeds convergence, e.g., for stacks of bodies"}, {Name: "Broad", Doc: "broad phase of collision detection, which works for any layout of the world tree"}, {Name: "Fluids", Doc: "regions of fluid that apply buoyancy and drag to movable bodies in Step"}, {Name: "Bounds", Doc: "bounds of the world, which can report, clamp or wrap around the objects that leave its Region, after they are stepped"}, {Name: "Vehicles", Doc: "wheeled vehicles whose wheels apply suspension and tire impulses to their chassis in Step"}, {Name: "Snaps", Doc: "scripted objects that are snapped onto the ground at the start of each Step, before their Rel values are applied"}, {Name: "Workers", Doc: "number of goroutines used to step the world in parallel, e.g., runtime.NumCPU() -- 0 or 1 is serial.  The independent subtrees under the world, the contacts of each body, and the islands of bodies connected by contacts are processed in parallel, with results identical to the serial run."}, {Name: "Flat", Doc: "use the flat BodyStore in Store for stepping the movable bodies and for the list of colliding bodies in the broad phase, instead of walking the tree, which is faster for large numbers of bodies -- Store.Invalidate must be called when bodies are added or removed, or change their flags, mass, locks, scale or shape"}, {Name: "Store", Doc: "flat storage of the state of the movable bodies, used when Flat is set"}, {Name: "Manifolds", Doc: "persistent contact manifolds from the last step, by pair of bodies"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.solverBody", IDName: "solver-body", Doc: "solverBody is the working state of a body during contact resolution", Fields: []types.Field{{Name: "bod"}, {Name: "invMass"}, {Name: "invI"}, {Name: "linVel"}, {Name: "angVel"}, {Name: "linF", Doc: "lock factors for linear and angular motion"}, {Name: "angF", Doc: "lock factors for linear and angular motion"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.solverContact", IDName: "solver-contact", Doc: "solverContact is the working state of a contact point during resolution", Fields: []types.Field{{Name: "ct"}, {Name: "mp"}, {Name: "a"}, {Name: "b"}, {Name: "ra"}, {Name: "rb"}, {Name: "target", Doc: "target minimum normal velocity"}, {Name: "touching", Doc: "true if the bodies are actually touching, not just about to"}, {Name: "impN", Doc: "accumulated normal impulse"}, {Name: "t1", Doc: "tangent axes for friction, with t1 along any anisotropic FrictionDir"}, {Name: "t2", Doc: "tangent axes for friction, with t1 along any anisotropic FrictionDir"}, {Name: "mu1", Doc: "dynamic and static friction coefficients along t1 and t2"}, {Name: "mu2", Doc: "dynamic and static friction coefficients along t1 and t2"}, {Name: "mus1", Doc: "dynamic and static friction coefficients along t1 and t2"}, {Name: "mus2", Doc: "dynamic and static friction coefficients along t1 and t2"}, {Name: "imp1", Doc: "accumulated friction impulses along t1 and t2"}, {Name: "imp2", Doc: "accumulated friction impulses along t1 and t2"}, {Name: "surfVel", Doc: "velocity of the surface of A vs. B, relative to the bodies (see Rigid.SurfaceVel)"}, {Name: "impR", Doc: "accumulated rolling friction angular impulse"}, {Name: "impS", Doc: "accumulated spinning friction angular impulse"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.resolveContact", IDName: "resolve-contact", Doc: "the contacts to resolve, and their indexes grouped by manifold,\nwhich are updated in parallel", Fields: []types.Field{{Name: "c"}, {Name: "sa"}, {Name: "sb"}, {Name: "scs"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.PlanarModes", IDName: "planar-modes", Doc: "PlanarModes are ways of constraining all dynamics to a plane"})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.SpatialHash", IDName: "spatial-hash", Doc: "SpatialHash is a uniform grid of cells in world coords, which is used\nto find the potential contacts between bodies for any layout of the\nworld tree, unlike WorldCollide, which depends on dynamic bodies being\norganized into separate groups.  It is rebuilt from the flags and\nvelocity-projected bounding boxes of all the bodies on each call to Collide.", Fields: []types.Field{{Name: "CellSize", Doc: "size of each cell -- if 0, it is set automatically to twice the average size of the dynamic bodies"}, {Name: "MaxCells", Doc: "maximum number of cells that a body can span before it is instead tested directly against all other bodies (e.g., large floors and walls)"}, {Name: "cell", Doc: "cell size used for the current hash"}, {Name: "cells", Doc: "indexes of bodies in each cell"}, {Name: "bods", Doc: "all the bodies in the world, in tree order"}, {Name: "big", Doc: "indexes of bodies that span more than MaxCells, or have empty bounding boxes"}, {Name: "Bounds", Doc: "bounds of the world, for finding contacts across the edges of a world that wraps around -- set from Solver.Bounds in Solver.Step"}, {Name: "Workers", Doc: "number of goroutines used to find the contacts of the dynamic bodies in parallel -- 0 or 1 is serial -- set from Solver.Workers in Solver.Step"}, {Name: "Store", Doc: "if non-nil and built for the world, its list of colliding bodies is used instead of walking the tree -- set from Solver.Store in Solver.Step when Solver.Flat is set"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.hashKey", IDName: "hash-key", Doc: "hashKey is the integer coordinates of a cell"})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.hashBody", IDName: "hash-body", Doc: "hashBody is a body in the SpatialHash", Fields: []types.Field{{Name: "bod"}, {Name: "top", Doc: "top-level group under the world that contains the body"}, {Name: "obj", Doc: "highest group under the world that contains the body and is a\nscripted object (see scriptedGroup), or nil if none"}, {Name: "dyn", Doc: "dynamic and movable status"}, {Name: "movable", Doc: "dynamic and movable status"}, {Name: "big", Doc: "true if in the big list"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.hashScratch", IDName: "hash-scratch", Doc: "hashScratch is the working memory for finding the contacts of bodies", Fields: []types.Field{{Name: "mark", Doc: "stamp of the last candidate search in which each body was added"}, {Name: "stamp", Doc: "current stamp"}, {Name: "cands", Doc: "candidate bodies for the current search"}}})
 
 // SphereType is the [types.Type] for [Sphere]
 var SphereType = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.Sphere", IDName: "sphere", Doc: "Sphere is a spherical body shape.", Embeds: []types.Field{{Name: "BodyBase"}}, Fields: []types.Field{{Name: "Radius", Doc: "radius"}}, Instance: &Sphere{}})
@@ -263,3 +542,15 @@ func (t *Sphere) SetVis(v string) *Sphere { t.Vis = v; return t }
 
 // SetColor sets the [Sphere.Color]
 func (t *Sphere) SetColor(v string) *Sphere { t.Color = v; return t }
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.BodyStore", IDName: "body-store", Doc: "BodyStore is a flat storage backend for the state of the movable bodies\nin a world (see IsMovable), which mirrors their Abs state, lock factors\nand bounding boxes into contiguous arrays (struct of arrays), so that\nintegration and bounding box refitting run over the arrays in a\ncache-friendly way, and keeps the list of all the colliding bodies for\nthe broad phase, so that the tree does not need to be walked through\ninterface calls on each step.  The tree remains the authoring view of\nthe world, and is synced at the boundaries of stepping: Load gathers\nthe state of the bodies from their nodes, and Save writes it back,\nwith the same results as StepPhys on each body.  The Solver uses a\nBodyStore in its Store when Flat is set.  The store is built from the\ntree by Sync, and must be rebuilt by calling Invalidate when bodies are\nadded or removed, or change their flags, mass, locks, scale or shape.", Fields: []types.Field{{Name: "Bodies", Doc: "the movable bodies, in tree order"}, {Name: "Pos", Doc: "Abs positions of the bodies"}, {Name: "Quat", Doc: "Abs rotations of the bodies"}, {Name: "LinVel", Doc: "Abs linear velocities of the bodies"}, {Name: "AngVel", Doc: "Abs angular velocities of the bodies"}, {Name: "LinF", Doc: "linear lock factors of the bodies (see Solver.LockFactors)"}, {Name: "AngF", Doc: "angular lock factors of the bodies (see Solver.LockFactors)"}, {Name: "Local", Doc: "bounding boxes of the shapes of the bodies in local body coords (see Body.LocalBBox)"}, {Name: "BBox", Doc: "bounding boxes of the bodies in world coords"}, {Name: "nodes", Doc: "nodes of the bodies"}, {Name: "colliders", Doc: "all the colliding bodies in the world, for the SpatialHash"}, {Name: "world", Doc: "world that the store was built for, nil if not built"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.DriveModes", IDName: "drive-modes", Doc: "DriveModes are the ways that a Vehicle is driven and steered"})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.Wheel", IDName: "wheel", Doc: "Wheel is a wheel of a Vehicle, which is attached to the chassis with a\nsuspension that extends straight down from its attachment point at Pos\nby up to Vehicle.SuspLen.", Fields: []types.Field{{Name: "Pos", Doc: "position of the attachment of the suspension in the local coordinates of the Chassis, which is the position of the center of the wheel when the suspension is fully compressed"}, {Name: "Radius", Doc: "radius of the wheel"}, {Name: "Driven", Doc: "whether the wheel is driven by the inputs"}, {Name: "Steer", Doc: "whether the wheel is steered by the Steer input, in Ackermann mode"}, {Name: "Caster", Doc: "whether the wheel is a caster that swivels freely, so that it does not resist sideways motion"}, {Name: "Vis", Doc: "optional visual-only body (see SetNoCollide), typically a Cylinder that is a Dynamic child of the Chassis, whose Rel pose is set on each step to show the position, steering and rotation of the wheel"}, {Name: "Speed", Doc: "angular speed of the wheel in radians per unit time, with positive rolling forward: set from the inputs for Driven wheels, and from the motion over the ground for other wheels"}, {Name: "SteerAngle", Doc: "current steering angle of the wheel in degrees, with positive to the left"}, {Name: "Angle", Doc: "current rotation angle of the wheel in radians"}, {Name: "Dist", Doc: "total distance rolled by the wheel, from its rotation (as measured by a wheel encoder), which differs from the distance actually traveled when the wheel slips"}, {Name: "Contact", Doc: "whether the wheel is touching the ground"}, {Name: "Susp", Doc: "current length of the suspension below Pos"}, {Name: "Slip", Doc: "velocity of the surface of the wheel relative to the ground at the point of contact, along (X) and across (Y) the direction of the wheel: 0 = rolling without slipping"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.Odometry", IDName: "odometry", Doc: "Odometry is the pose of a Vehicle on the ground estimated from the\nrotation of its wheels (dead reckoning), as a wheeled robot would\ncompute it from its wheel encoders, which drifts from the actual pose\nwhen the wheels slip.", Fields: []types.Field{{Name: "Pos", Doc: "estimated position in world coordinates, in the horizontal XZ plane"}, {Name: "Heading", Doc: "estimated heading in degrees, as the rotation about the Y axis from facing along -Z, with positive to the left"}, {Name: "Dist", Doc: "total distance traveled"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.Vehicle", IDName: "vehicle", Doc: "Vehicle is a wheeled vehicle, which moves a movable Chassis body\naccording to the speeds of its Driven Wheels and the steering angle,\nusing a raycast vehicle model: the ground under each wheel is found by\ncasting a ray down from its suspension (see GroundAt), the suspension\nsupports the chassis with a spring and damper, and the tires apply\nfriction impulses that drive the point of contact toward rolling without\nslipping along the wheel, and resist sliding across it, up to the\nlimit of Friction.  The wheels themselves are not bodies, and only the\nChassis collides with the world.  Add it to Solver.Vehicles so that\nStep is called on each Solver.Step.", Fields: []types.Field{{Name: "Chassis", Doc: "the body of the vehicle, which must be movable (see IsMovable) -- its collision shape should be above the ground when resting on the wheels"}, {Name: "Mode", Doc: "how the vehicle is driven and steered"}, {Name: "Wheels", Doc: "the wheels of the vehicle"}, {Name: "Friction", Doc: "coefficient of friction between the wheels and the ground"}, {Name: "SuspLen", Doc: "length of the travel of the suspension of each wheel"}, {Name: "SuspStiff", Doc: "stiffness of the suspension spring of each wheel, per unit mass of the chassis"}, {Name: "SuspDamp", Doc: "damping of the suspension of each wheel, per unit mass of the chassis"}, {Name: "MaxSteer", Doc: "maximum steering angle in degrees"}, {Name: "Speed", Doc: "drive speed in Ackermann mode in radians per unit time, for a wheel at the center of the rear axle: in a turn, each Driven wheel is driven in proportion to its distance from the center of the turn, as with a differential"}, {Name: "Steer", Doc: "steering angle in degrees in Ackermann mode, with positive to the left"}, {Name: "Odom", Doc: "pose estimated from the rotation of the wheels"}, {Name: "odomInit", Doc: "whether Odom has been initialized"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/eve.wheelTire", IDName: "wheel-tire", Doc: "wheelTire is the working state of the contact of a wheel with the ground", Fields: []types.Field{{Name: "wh"}, {Name: "r", Doc: "point of contact relative to the chassis center"}, {Name: "fwd", Doc: "directions along and across the wheel, in the ground plane"}, {Name: "side", Doc: "directions along and across the wheel, in the ground plane"}, {Name: "maxJ", Doc: "maximum friction impulse, from the load on the wheel"}, {Name: "jf", Doc: "accumulated friction impulses along and across the wheel"}, {Name: "js", Doc: "accumulated friction impulses along and across the wheel"}}})
@@ -0,0 +1,437 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package eve
+
+import (
+	"cogentcore.org/core/math32"
+)
+
+// DriveModes are the ways that a Vehicle is driven and steered
+type DriveModes int32 //enums:enum
+
+const (
+	// DiffDrive is differential drive: the Driven wheels on the left
+	// (negative X) and right (positive X) sides are driven at separate
+	// speeds, and the vehicle turns by driving them at different speeds
+	// (e.g., a two-wheeled robot with a Caster wheel).
+	DiffDrive DriveModes = iota
+
+	// Ackermann is car-like steering: the Steer wheels are turned by the
+	// Steer angle, with the inner wheel turned more so that all the wheels
+	// roll around the same center, and the Driven wheels are driven at the
+	// Speed, scaled by their distance from that center.
+	Ackermann
+)
+
+// Wheel is a wheel of a Vehicle, which is attached to the chassis with a
+// suspension that extends straight down from its attachment point at Pos
+// by up to Vehicle.SuspLen.
+type Wheel struct {
+
+	// position of the attachment of the suspension in the local coordinates of the Chassis, which is the position of the center of the wheel when the suspension is fully compressed
+	Pos math32.Vector3
+
+	// radius of the wheel
+	Radius float32
+
+	// whether the wheel is driven by the inputs
+	Driven bool
+
+	// whether the wheel is steered by the Steer input, in Ackermann mode
+	Steer bool
+
+	// whether the wheel is a caster that swivels freely, so that it does not resist sideways motion
+	Caster bool
+
+	// optional visual-only body (see SetNoCollide), typically a Cylinder that is a Dynamic child of the Chassis, whose Rel pose is set on each step to show the position, steering and rotation of the wheel
+	Vis Body
+
+	// angular speed of the wheel in radians per unit time, with positive rolling forward: set from the inputs for Driven wheels, and from the motion over the ground for other wheels
+	Speed float32 `edit:"-"`
+
+	// current steering angle of the wheel in degrees, with positive to the left
+	SteerAngle float32 `edit:"-"`
+
+	// current rotation angle of the wheel in radians
+	Angle float32 `edit:"-"`
+
+	// total distance rolled by the wheel, from its rotation (as measured by a wheel encoder), which differs from the distance actually traveled when the wheel slips
+	Dist float32 `edit:"-"`
+
+	// whether the wheel is touching the ground
+	Contact bool `edit:"-"`
+
+	// current length of the suspension below Pos
+	Susp float32 `edit:"-"`
+
+	// velocity of the surface of the wheel relative to the ground at the point of contact, along (X) and across (Y) the direction of the wheel: 0 = rolling without slipping
+	Slip math32.Vector2 `edit:"-"`
+}
+
+// Odometry is the pose of a Vehicle on the ground estimated from the
+// rotation of its wheels (dead reckoning), as a wheeled robot would
+// compute it from its wheel encoders, which drifts from the actual pose
+// when the wheels slip.
+type Odometry struct {
+
+	// estimated position in world coordinates, in the horizontal XZ plane
+	Pos math32.Vector3
+
+	// estimated heading in degrees, as the rotation about the Y axis from facing along -Z, with positive to the left
+	Heading float32
+
+	// total distance traveled
+	Dist float32
+}
+
+// Vehicle is a wheeled vehicle, which moves a movable Chassis body
+// according to the speeds of its Driven Wheels and the steering angle,
+// using a raycast vehicle model: the ground under each wheel is found by
+// casting a ray down from its suspension (see GroundAt), the suspension
+// supports the chassis with a spring and damper, and the tires apply
+// friction impulses that drive the point of contact toward rolling without
+// slipping along the wheel, and resist sliding across it, up to the
+// limit of Friction.  The wheels themselves are not bodies, and only the
+// Chassis collides with the world.  Add it to Solver.Vehicles so that
+// Step is called on each Solver.Step.
+type Vehicle struct {
+
+	// the body of the vehicle, which must be movable (see IsMovable) -- its collision shape should be above the ground when resting on the wheels
+	Chassis Body
+
+	// how the vehicle is driven and steered
+	Mode DriveModes
+
+	// the wheels of the vehicle
+	Wheels []Wheel
+
+	// coefficient of friction between the wheels and the ground
+	Friction float32 `default:"1"`
+
+	// length of the travel of the suspension of each wheel
+	SuspLen float32 `default:"0.1"`
+
+	// stiffness of the suspension spring of each wheel, per unit mass of the chassis
+	SuspStiff float32 `default:"100"`
+
+	// damping of the suspension of each wheel, per unit mass of the chassis
+	SuspDamp float32 `default:"10"`
+
+	// maximum steering angle in degrees
+	MaxSteer float32 `default:"35"`
+
+	// drive speed in Ackermann mode in radians per unit time, for a wheel at the center of the rear axle: in a turn, each Driven wheel is driven in proportion to its distance from the center of the turn, as with a differential
+	Speed float32
+
+	// steering angle in degrees in Ackermann mode, with positive to the left
+	Steer float32
+
+	// pose estimated from the rotation of the wheels
+	Odom Odometry `edit:"-"`
+
+	// whether Odom has been initialized
+	odomInit bool
+}
+
+// NewVehicle returns a new Vehicle with given chassis body and drive mode,
+// with default parameters and no wheels
+func NewVehicle(chassis Body, mode DriveModes) *Vehicle {
+	vh := &Vehicle{Chassis: chassis, Mode: mode}
+	vh.Defaults()
+	return vh
+}
+
+func (vh *Vehicle) Defaults() {
+	vh.Friction = 1
+	vh.SuspLen = 0.1
+	vh.SuspStiff = 100
+	vh.SuspDamp = 10
+	vh.MaxSteer = 35
+}
+
+// AddWheel adds a wheel at given position in chassis coordinates with
+// given radius, returning it for setting further options
+func (vh *Vehicle) AddWheel(pos math32.Vector3, radius float32, driven bool) *Wheel {
+	vh.Wheels = append(vh.Wheels, Wheel{Pos: pos, Radius: radius, Driven: driven})
+	return &vh.Wheels[len(vh.Wheels)-1]
+}
+
+// SetWheelSpeeds sets the speeds of the Driven wheels on the left
+// (negative X) and right (positive X) sides, in radians per unit time,
+// for DiffDrive mode
+func (vh *Vehicle) SetWheelSpeeds(left, right float32) {
+	for i := range vh.Wheels {
+		wh := &vh.Wheels[i]
+		if !wh.Driven {
+			continue
+		}
+		if wh.Pos.X < 0 {
+			wh.Speed = left
+		} else {
+			wh.Speed = right
+		}
+	}
+}
+
+// SetDrive sets the Speed of the Driven wheels in radians per unit time,
+// and the Steer angle in degrees, for Ackermann mode
+func (vh *Vehicle) SetDrive(speed, steer float32) {
+	vh.Speed = speed
+	vh.Steer = steer
+}
+
+// ResetOdom resets the Odom pose to the current pose of the Chassis
+func (vh *Vehicle) ResetOdom() {
+	nb := vh.Chassis.AsNodeBase()
+	vh.Odom.Pos = nb.Abs.Pos
+	fwd := math32.Vec3(0, 0, -1).MulQuat(nb.Abs.Quat)
+	vh.Odom.Heading = math32.RadToDeg(math32.Atan2(-fwd.X, -fwd.Z))
+	vh.Odom.Dist = 0
+	vh.odomInit = true
+}
+
+// setSteer sets the speeds of the Driven wheels and the steering angles
+// of the Steer wheels for Ackermann mode, from the turning radius given by
+// the Steer angle and the distance between the front and rear wheels
+func (vh *Vehicle) setSteer() {
+	if vh.Mode != Ackermann {
+		return
+	}
+	steer := math32.DegToRad(math32.Clamp(vh.Steer, -vh.MaxSteer, vh.MaxSteer))
+	var frontZ, rearZ float32
+	var nf, nr int
+	for _, wh := range vh.Wheels {
+		if wh.Steer {
+			frontZ += wh.Pos.Z
+			nf++
+		} else {
+			rearZ += wh.Pos.Z
+			nr++
+		}
+	}
+	if nf > 0 {
+		frontZ /= float32(nf)
+	}
+	if nr > 0 {
+		rearZ /= float32(nr)
+	}
+	for i := range vh.Wheels {
+		wh := &vh.Wheels[i]
+		if steer == 0 || rearZ == frontZ {
+			wh.SteerAngle = 0
+			if wh.Driven {
+				wh.Speed = vh.Speed
+			}
+			continue
+		}
+		rad := (rearZ - frontZ) / math32.Tan(steer) // turning radius, positive to the left
+		base := rearZ - wh.Pos.Z
+		if wh.Driven {
+			wh.Speed = vh.Speed * math32.Sqrt((rad+wh.Pos.X)*(rad+wh.Pos.X)+base*base) / math32.Abs(rad)
+		}
+		if wh.Steer {
+			wh.SteerAngle = math32.RadToDeg(math32.Atan(base / (rad + wh.Pos.X)))
+		}
+	}
+}
+
+// Step applies the suspension and tire impulses of all the wheels to the
+// velocity of the Chassis for given step, using the ground in given world,
+// and updates the rotation of the wheels, the Odom pose, and the Vis bodies
+// of the wheels.  This is called by Solver.Step for all of its Vehicles,
+// after gravity is applied and before contacts are resolved.
+func (vh *Vehicle) Step(sv *Solver, world *Group, step float32) {
+	if vh.Chassis == nil || !IsMovable(vh.Chassis) || step <= 0 {
+		return
+	}
+	if !vh.odomInit {
+		vh.ResetOdom()
+	}
+	vh.setSteer()
+	nb := vh.Chassis.AsNodeBase()
+	sb := sv.newSolverBody(vh.Chassis, step)
+	mass := 1 / sb.invMass
+	q := nb.Abs.Quat
+	sc := nb.Abs.ScaleFactor()
+	tires := make([]wheelTire, 0, len(vh.Wheels))
+	for i := range vh.Wheels {
+		wh := &vh.Wheels[i]
+		wh.Slip = math32.Vector2{}
+		hp := wh.Pos.Mul(sc).MulQuat(q).Add(nb.Abs.Pos)
+		gh, ok := world.GroundAt(hp, vh.SuspLen+wh.Radius)
+		wh.Contact = ok
+		if !ok {
+			wh.Susp = vh.SuspLen
+			continue
+		}
+		wh.Susp = max(gh.Dist-wh.Radius, 0)
+		tr := wheelTire{wh: wh, r: gh.Point.Sub(nb.Abs.Pos)}
+		n := gh.Normal
+		vn := sb.velAt(tr.r).Dot(n)
+		load := mass * max(vh.SuspStiff*(vh.SuspLen-wh.Susp)-vh.SuspDamp*vn, 0) * step
+		sb.applyImpulse(n.MulScalar(load), tr.r)
+		sa := math32.DegToRad(wh.SteerAngle)
+		fwd := math32.Vec3(-math32.Sin(sa), 0, -math32.Cos(sa)).MulQuat(q)
+		fwd.SetSub(n.MulScalar(fwd.Dot(n)))
+		if fwd.Length() < 1.0e-6 {
+			continue
+		}
+		tr.fwd = fwd.Normal()
+		tr.side = n.Cross(tr.fwd)
+		tr.maxJ = vh.Friction * load
+		tires = append(tires, tr)
+	}
+	for iter := 0; iter < max(sv.Iters, 1); iter++ {
+		for ti := range tires {
+			tires[ti].solve(sb)
+		}
+	}
+	for _, tr := range tires {
+		wh := tr.wh
+		vel := sb.velAt(tr.r)
+		vf := vel.Dot(tr.fwd)
+		if wh.Driven {
+			wh.Slip.X = wh.Speed*wh.Radius - vf
+		} else {
+			wh.Speed = vf / wh.Radius
+		}
+		if !wh.Caster {
+			wh.Slip.Y = -vel.Dot(tr.side)
+		}
+	}
+	sb.store()
+	vh.stepWheels(step)
+}
+
+// wheelTire is the working state of the contact of a wheel with the ground
+type wheelTire struct {
+	wh *Wheel
+
+	// point of contact relative to the chassis center
+	r math32.Vector3
+
+	// directions along and across the wheel, in the ground plane
+	fwd, side math32.Vector3
+
+	// maximum friction impulse, from the load on the wheel
+	maxJ float32
+
+	// accumulated friction impulses along and across the wheel
+	jf, js float32
+}
+
+// solve applies the friction impulses that drive the point of contact
+// toward rolling without slipping, within the limit of maxJ on the total
+// accumulated impulse
+func (tr *wheelTire) solve(sb *solverBody) {
+	vel := sb.velAt(tr.r)
+	jf, js := tr.jf, tr.js
+	if tr.wh.Driven {
+		if em := sb.effMass(tr.fwd, tr.r); em > 0 {
+			jf += (tr.wh.Speed*tr.wh.Radius - vel.Dot(tr.fwd)) / em
+		}
+	}
+	if !tr.wh.Caster {
+		if em := sb.effMass(tr.side, tr.r); em > 0 {
+			js -= vel.Dot(tr.side) / em
+		}
+	}
+	if jm := math32.Sqrt(jf*jf + js*js); jm > tr.maxJ {
+		jf *= tr.maxJ / jm
+		js *= tr.maxJ / jm
+	}
+	sb.applyImpulse(tr.fwd.MulScalar(jf-tr.jf).Add(tr.side.MulScalar(js-tr.js)), tr.r)
+	tr.jf, tr.js = jf, js
+}
+
+// stepWheels updates the rotation of the wheels, the Odom pose from their
+// rotation, and the Vis bodies of the wheels, for given step
+func (vh *Vehicle) stepWheels(step float32) {
+	var dl, dr, dd, xl, xr, zf, zb float32
+	var nl, nr, nd, nf, nb int
+	var pd math32.Vector3 // center of the driven wheels, which the odometry follows
+	for i := range vh.Wheels {
+		wh := &vh.Wheels[i]
+		d := wh.Speed * wh.Radius * step
+		wh.Angle += wh.Speed * step
+		wh.Dist += d
+		vh.setVis(wh)
+		if wh.Steer {
+			zf += wh.Pos.Z
+			nf++
+		} else {
+			zb += wh.Pos.Z
+			nb++
+		}
+		if !wh.Driven {
+			continue
+		}
+		dd += d
+		pd.SetAdd(wh.Pos)
+		nd++
+		if wh.Pos.X < 0 {
+			dl += d
+			xl += wh.Pos.X
+			nl++
+		} else {
+			dr += d
+			xr += wh.Pos.X
+			nr++
+		}
+	}
+	var dist, turn float32
+	switch {
+	case vh.Mode == DiffDrive && nl > 0 && nr > 0:
+		dl, dr = dl/float32(nl), dr/float32(nr)
+		track := xr/float32(nr) - xl/float32(nl)
+		dist = 0.5 * (dl + dr)
+		if track > 0 {
+			turn = (dr - dl) / track
+		}
+	case nd > 0:
+		dist = dd / float32(nd)
+		if vh.Mode == Ackermann && nf > 0 && nb > 0 {
+			base := zb/float32(nb) - zf/float32(nf)
+			steer := math32.DegToRad(math32.Clamp(vh.Steer, -vh.MaxSteer, vh.MaxSteer))
+			if base != 0 {
+				turn = dist * math32.Tan(steer) / base
+			}
+		}
+	}
+	if nd > 0 {
+		pd.SetDivScalar(float32(nd))
+	}
+	pd.Y = 0
+	h0 := math32.DegToRad(vh.Odom.Heading)
+	hd := h0 + 0.5*turn
+	vh.Odom.Pos.SetAdd(math32.Vec3(-math32.Sin(hd), 0, -math32.Cos(hd)).MulScalar(dist))
+	vh.Odom.Heading += math32.RadToDeg(turn)
+	// move from the center of the driven wheels to the origin of the Chassis
+	up := math32.Vec3(0, 1, 0)
+	po := pd.Negate()
+	vh.Odom.Pos.SetAdd(po.MulQuat(math32.NewQuatAxisAngle(up, h0+turn)).Sub(po.MulQuat(math32.NewQuatAxisAngle(up, h0))))
+	vh.Odom.Dist += math32.Abs(dist)
+}
+
+// setVis sets the Rel pose of the Vis body of given wheel, if any
+func (vh *Vehicle) setVis(wh *Wheel) {
+	if wh.Vis == nil {
+		return
+	}
+	nb := wh.Vis.AsNodeBase()
+	nb.Rel.Pos = wh.Pos.Sub(math32.Vec3(0, wh.Susp, 0))
+	qs := math32.NewQuatAxisAngle(math32.Vec3(0, 1, 0), math32.DegToRad(wh.SteerAngle))
+	qr := math32.NewQuatAxisAngle(math32.Vec3(1, 0, 0), -wh.Angle)
+	qa := math32.NewQuatAxisAngle(math32.Vec3(0, 0, 1), math32.Pi/2) // cylinder axis along X
+	qsr := qs.Mul(qr)
+	nb.Rel.Quat = qsr.Mul(qa)
+}
+
+// ApplyVehicles calls Step on all of the Vehicles
+func (sv *Solver) ApplyVehicles(world *Group, step float32) {
+	for _, vh := range sv.Vehicles {
+		vh.Step(sv, world, step)
+	}
+}
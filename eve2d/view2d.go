@@ -101,7 +101,7 @@ func (vw *View) Prjn2D(pos math32.Vector3) math32.Vector2 {
 	return math32.Vec2(v2.X, v2.Y)
 }
 
-// Transform2D returns the full 2D transform matrix for a given position and quat rotation in 3D
+// Transform2D returns the full 2D transform matrix for a given position, quat rotation and scale in 3D
 func (vw *View) Transform2D(phys *eve.Phys) math32.Matrix2 {
 	pos2 := phys.Pos.MulMatrix4(&vw.Prjn)
 	xyaxis := math32.Vec3(1, 1, 0)
@@ -115,7 +115,8 @@ func (vw *View) Transform2D(phys *eve.Phys) math32.Matrix2 {
 	xyrot.Z = 0
 	xyrot.SetNormal()
 	ang := xyrot.AngleTo(xyaxis)
-	xf2 := math32.Translate2D(pos2.X, pos2.Y).Rotate(ang)
+	sc := vw.Prjn2D(phys.ScaleFactor())
+	xf2 := math32.Translate2D(pos2.X, pos2.Y).Rotate(ang).Scale(sc.X, sc.Y)
 	return xf2
 }
 
@@ -156,10 +157,14 @@ func (vw *View) InitLibraryBody(wn eve.Node) {
 	}
 }
 
-// InitLibShape initializes Scene library with basic shape for given body
+// InitLibShape initializes Scene library with basic shape for given body.
+// Proxy bodies are skipped, as they show their own Vis library entry.
 func (vw *View) InitLibShape(bod eve.Body) {
 	nm := bod.Name()
 	bb := bod.AsBodyBase()
+	if bb.Is(eve.Proxy) {
+		return
+	}
 	if bb.Vis == "" {
 		bb.Vis = nm
 	}
@@ -169,7 +174,7 @@ func (vw *View) InitLibShape(bod eve.Body) {
 	lgp := vw.NewInLibrary(nm)
 	wt := bod.NodeType().ShortName()
 	switch wt {
-	case "eve.Box":
+	case "eve.Box", "eve.Hull":
 		mnm := "eveBox"
 		svg.NewRect(lgp, mnm).SetPos(math32.Vec2(0, 0)).SetSize(math32.Vec2(1, 1))
 	case "eve.Cylinder":
@@ -184,8 +189,12 @@ func (vw *View) InitLibShape(bod eve.Body) {
 	}
 }
 
-// ConfigBodyShape configures a shape for a body with current values
+// ConfigBodyShape configures a shape for a body with current values.
+// The Vis of Proxy bodies is shown as is.
 func (vw *View) ConfigBodyShape(bod eve.Body, shp svg.Node) {
+	if bod.AsNodeBase().Is(eve.Proxy) {
+		return
+	}
 	wt := bod.NodeType().ShortName()
 	sb := shp.AsNodeBase()
 	sb.Nm = bod.Name()
@@ -237,6 +246,19 @@ func (vw *View) ConfigBodyShape(bod eve.Body, shp svg.Node) {
 		if sp.Color != "" {
 			shp.SetProperty("stroke", sp.Color)
 		}
+	case "eve.Hull":
+		hl := bod.(*eve.Hull)
+		lb := hl.Bounds()
+		sz := vw.Prjn2D(lb.Size())
+		mn := vw.Prjn2D(lb.Min)
+		shp.(*svg.Rect).SetSize(sz)
+		sb.Paint.Transform = math32.Translate2D(mn.X, mn.Y)
+		shp.SetProperty("transform", sb.Paint.Transform.String())
+		shp.SetProperty("stroke-width", vw.LineWidth)
+		shp.SetProperty("fill", "none")
+		if hl.Color != "" {
+			shp.SetProperty("stroke", hl.Color)
+		}
 	}
 }
 
@@ -250,8 +272,15 @@ func (vw *View) ConfigView(wn eve.Node, vn svg.Node) {
 	if bod == nil {
 		return
 	}
+	vis := bod.AsBodyBase().Vis
+	if vis == "" { // e.g., an invisible Proxy
+		return
+	}
 	if !vb.HasChildren() {
-		vw.AddFromLibrary(bod.AsBodyBase().Vis, vb)
+		vw.AddFromLibrary(vis, vb)
+	}
+	if !vb.HasChildren() {
+		return
 	}
 	bgp := vb.Child(0)
 	if bgp.HasChildren() {
@@ -325,7 +354,7 @@ func (vw *View) UpdateBodyViewNode(bodyNames []string, wn eve.Node, vn svg.Node)
 				}
 			}
 		}
-		if match {
+		if match && vk.HasChildren() {
 			bgp := vk.Child(0)
 			if bgp.HasChildren() {
 				shp, has := bgp.Child(0).(svg.Node)
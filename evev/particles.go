@@ -0,0 +1,116 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package evev
+
+import (
+	"cogentcore.org/core/base/errors"
+	"cogentcore.org/core/colors"
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/vgpu/vshape"
+	"cogentcore.org/core/xyz"
+	"github.com/emer/eve/v2/eve"
+)
+
+// particleVerts are the directions of the vertices of the octahedron
+// that is drawn for each particle
+var particleVerts = [6]math32.Vector3{{1, 0, 0}, {-1, 0, 0}, {0, 1, 0}, {0, -1, 0}, {0, 0, 1}, {0, 0, -1}}
+
+// particleFaces are the triangles of the octahedron, as indexes into particleVerts
+var particleFaces = [8][3]int{{0, 2, 4}, {4, 2, 1}, {1, 2, 5}, {5, 2, 0}, {4, 3, 0}, {1, 3, 4}, {5, 3, 1}, {0, 3, 5}}
+
+// ParticlesMesh is a dynamic mesh that draws eve.Particles, as a small
+// octahedron for each particle, with room for the Max number of particles.
+// The vertices are in the local coordinates of the Particles node, and are
+// updated from the current particle positions on each View.UpdatePose.
+type ParticlesMesh struct {
+	xyz.MeshBase
+
+	// the particles that are drawn
+	Particles *eve.Particles
+
+	// maximum number of particles that can be drawn, from Particles.Max when the mesh was made
+	Max int
+}
+
+// NewParticlesMesh adds a new ParticlesMesh with given name for given
+// particles to given scene
+func NewParticlesMesh(sc *xyz.Scene, name string, ps *eve.Particles) *ParticlesMesh {
+	pm := &ParticlesMesh{Particles: ps, Max: ps.Max}
+	pm.Nm = name
+	pm.Dynamic = true
+	sc.AddMesh(pm)
+	return pm
+}
+
+func (pm *ParticlesMesh) Sizes() (nVtx, nIndex int, hasColor bool) {
+	tvn, tin := vshape.TriangleN()
+	nf := len(particleFaces) * pm.Max
+	pm.NVtx, pm.NIndex = nf*tvn, nf*tin
+	return pm.NVtx, pm.NIndex, false
+}
+
+func (pm *ParticlesMesh) Set(sc *xyz.Scene, vtxAry, normAry, texAry, clrAry math32.ArrayF32, idxAry math32.ArrayU32) {
+	pm.setVerts(vtxAry, normAry, texAry, idxAry)
+}
+
+func (pm *ParticlesMesh) Update(sc *xyz.Scene, vtxAry, normAry, texAry, clrAry math32.ArrayF32, idxAry math32.ArrayU32) {
+	pm.setVerts(vtxAry, normAry, texAry, idxAry)
+	pm.SetMod(sc)
+}
+
+// setVerts sets the vertices from the current positions of the particles,
+// collapsing the octahedra of unused particles to a point
+func (pm *ParticlesMesh) setVerts(vtxAry, normAry, texAry math32.ArrayF32, idxAry math32.ArrayU32) {
+	ps := pm.Particles
+	iq := ps.Abs.Quat.Conjugate()
+	sc := ps.Abs.ScaleFactor()
+	tvn, tin := vshape.TriangleN()
+	var pos math32.Vector3
+	bb := math32.B3Empty()
+	vo, io := 0, 0
+	for i := range pm.Max {
+		var ctr math32.Vector3
+		r := float32(0)
+		if i < len(ps.Pos) {
+			ctr = ps.Pos[i].Sub(ps.Abs.Pos).MulQuat(iq).Div(sc)
+			r = ps.Radius
+		}
+		var vs [6]math32.Vector3
+		for k, d := range particleVerts {
+			vs[k] = ctr.Add(d.MulScalar(r).Div(sc))
+		}
+		for _, f := range particleFaces {
+			tb := vshape.SetTriangle(vtxAry, normAry, texAry, idxAry, vo, io, vs[f[0]], vs[f[1]], vs[f[2]], nil, pos)
+			if r > 0 {
+				bb.ExpandByBox(tb)
+			}
+			vo += tvn
+			io += tin
+		}
+	}
+	if bb.IsEmpty() {
+		bb = math32.Box3{}
+	}
+	pm.BBoxMu.Lock()
+	pm.BBox.SetBounds(bb.Min, bb.Max)
+	pm.BBoxMu.Unlock()
+}
+
+// ConfigParticles configures the view node for given particles, adding
+// a Solid that draws them with a ParticlesMesh
+func (vw *View) ConfigParticles(ps *eve.Particles, vb *xyz.Group, sc *xyz.Scene) {
+	vw.dynMeshes = true
+	mnm := "eveParticles:" + ps.Path()
+	if !vb.HasChildren() {
+		if sc.MeshByName(mnm) == nil {
+			NewParticlesMesh(sc, mnm, ps)
+		}
+		xyz.NewSolid(vb, ps.Name()).SetMeshName(mnm)
+	}
+	sld, has := vb.Child(0).(*xyz.Solid)
+	if has && ps.Color != "" {
+		sld.Mat.Color = errors.Log1(colors.FromString(ps.Color))
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package evev
+
+import (
+	"cogentcore.org/core/base/errors"
+	"cogentcore.org/core/colors"
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/vgpu/vshape"
+	"cogentcore.org/core/xyz"
+	"github.com/emer/eve/v2/eve"
+)
+
+// SoftMesh is a dynamic mesh that draws an eve.Soft body, as a two-sided
+// surface of its Tris if it has any (e.g., cloth), and otherwise as thin
+// square tubes along its Links (e.g., a rope).  The vertices are in the
+// local coordinates of the Soft node, and are updated from its current
+// point positions on each View.UpdatePose.
+type SoftMesh struct {
+	xyz.MeshBase
+
+	// the soft body that is drawn
+	Soft *eve.Soft
+}
+
+// NewSoftMesh adds a new SoftMesh with given name for given soft body
+// to given scene
+func NewSoftMesh(sc *xyz.Scene, name string, sf *eve.Soft) *SoftMesh {
+	sm := &SoftMesh{Soft: sf}
+	sm.Nm = name
+	sm.Dynamic = true
+	sc.AddMesh(sm)
+	return sm
+}
+
+func (sm *SoftMesh) Sizes() (nVtx, nIndex int, hasColor bool) {
+	if nt := len(sm.Soft.Tris) / 3; nt > 0 {
+		tvn, tin := vshape.TriangleN()
+		sm.NVtx, sm.NIndex = 2*nt*tvn, 2*nt*tin
+	} else {
+		qvn, qin := vshape.QuadN()
+		nl := len(sm.Soft.Links)
+		sm.NVtx, sm.NIndex = 4*nl*qvn, 4*nl*qin
+	}
+	return sm.NVtx, sm.NIndex, false
+}
+
+func (sm *SoftMesh) Set(sc *xyz.Scene, vtxAry, normAry, texAry, clrAry math32.ArrayF32, idxAry math32.ArrayU32) {
+	sm.setVerts(vtxAry, normAry, texAry, idxAry)
+}
+
+func (sm *SoftMesh) Update(sc *xyz.Scene, vtxAry, normAry, texAry, clrAry math32.ArrayF32, idxAry math32.ArrayU32) {
+	sm.setVerts(vtxAry, normAry, texAry, idxAry)
+	sm.SetMod(sc)
+}
+
+// setVerts sets the vertices from the current positions of the points
+func (sm *SoftMesh) setVerts(vtxAry, normAry, texAry math32.ArrayF32, idxAry math32.ArrayU32) {
+	sf := sm.Soft
+	iq := sf.Abs.Quat.Conjugate()
+	sc := sf.Abs.ScaleFactor()
+	pts := make([]math32.Vector3, len(sf.Pos))
+	for i, p := range sf.Pos {
+		pts[i] = p.Sub(sf.Abs.Pos).MulQuat(iq).Div(sc)
+	}
+	var pos math32.Vector3
+	bb := math32.B3Empty()
+	vo, io := 0, 0
+	if nt := len(sf.Tris) / 3; nt > 0 {
+		tvn, tin := vshape.TriangleN()
+		for t := range nt {
+			a, b, c := pts[sf.Tris[3*t]], pts[sf.Tris[3*t+1]], pts[sf.Tris[3*t+2]]
+			bb.ExpandByBox(vshape.SetTriangle(vtxAry, normAry, texAry, idxAry, vo, io, a, b, c, nil, pos))
+			vshape.SetTriangle(vtxAry, normAry, texAry, idxAry, vo+tvn, io+tin, a, c, b, nil, pos)
+			vo += 2 * tvn
+			io += 2 * tin
+		}
+	} else {
+		qvn, qin := vshape.QuadN()
+		r := sf.Radius
+		for _, lk := range sf.Links {
+			a, b := pts[lk.A], pts[lk.B]
+			d := b.Sub(a)
+			if d.Length() == 0 {
+				d.Set(0, 1, 0)
+			}
+			d.SetNormal()
+			u := d.Cross(math32.Vec3(1, 0, 0))
+			if u.Length() < 0.1 {
+				u = d.Cross(math32.Vec3(0, 0, 1))
+			}
+			u = u.Normal().MulScalar(r)
+			v := d.Cross(u)
+			cs := [4]math32.Vector3{u, v, u.Negate(), v.Negate()}
+			for k := range 4 {
+				c0, c1 := cs[k], cs[(k+1)%4]
+				q := []math32.Vector3{a.Add(c0), a.Add(c1), b.Add(c1), b.Add(c0)}
+				bb.ExpandByBox(vshape.SetQuad(vtxAry, normAry, texAry, idxAry, vo, io, q, nil, pos))
+				vo += qvn
+				io += qin
+			}
+		}
+	}
+	if bb.IsEmpty() {
+		bb = math32.Box3{}
+	}
+	sm.BBoxMu.Lock()
+	sm.BBox.SetBounds(bb.Min, bb.Max)
+	sm.BBoxMu.Unlock()
+}
+
+// ConfigSoft configures the view node for given soft body, adding a
+// Solid that draws it with a SoftMesh
+func (vw *View) ConfigSoft(sf *eve.Soft, vb *xyz.Group, sc *xyz.Scene) {
+	vw.dynMeshes = true
+	mnm := "eveSoft:" + sf.Path()
+	if !vb.HasChildren() {
+		if sc.MeshByName(mnm) == nil {
+			NewSoftMesh(sc, mnm, sf)
+		}
+		xyz.NewSolid(vb, sf.Name()).SetMeshName(mnm)
+	}
+	sld, has := vb.Child(0).(*xyz.Solid)
+	if has && sf.Color != "" {
+		sld.Mat.Color = errors.Log1(colors.FromString(sf.Color))
+	}
+}
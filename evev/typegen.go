@@ -8,4 +8,8 @@ import (
 
 var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/evev.Camera", IDName: "camera", Doc: "Camera defines the properties of a camera needed for offscreen rendering", Fields: []types.Field{{Name: "Size", Doc: "size of image to record"}, {Name: "FOV", Doc: "field of view in degrees"}, {Name: "Near", Doc: "near plane z coordinate"}, {Name: "Far", Doc: "far plane z coordinate"}, {Name: "MaxD", Doc: "maximum distance for depth maps -- anything above is 1 -- this is independent of Near / Far rendering (though must be < Far) and is for normalized depth maps"}, {Name: "LogD", Doc: "use the natural log of 1 + depth for normalized depth values in display etc"}, {Name: "MSample", Doc: "number of multi-samples to use for antialising -- 4 is best and default"}, {Name: "UpDir", Doc: "up direction for camera -- which way is up -- defaults to positive Y axis, and is reset by call to LookAt method"}}})
 
-var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/evev.View", IDName: "view", Doc: "View connects a Virtual World with a Xyz Scene to visualize the world,\nincluding ability to render offscreen", Fields: []types.Field{{Name: "World", Doc: "the root Group node of the virtual world"}, {Name: "Scene", Doc: "the scene object for visualizing"}, {Name: "Root", Doc: "the root Group node in the Scene under which the world is rendered"}}})
+var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/evev.ParticlesMesh", IDName: "particles-mesh", Doc: "ParticlesMesh is a dynamic mesh that draws eve.Particles, as a small\noctahedron for each particle, with room for the Max number of particles.\nThe vertices are in the local coordinates of the Particles node, and are\nupdated from the current particle positions on each View.UpdatePose.", Embeds: []types.Field{{Name: "MeshBase"}}, Fields: []types.Field{{Name: "Particles", Doc: "the particles that are drawn"}, {Name: "Max", Doc: "maximum number of particles that can be drawn, from Particles.Max when the mesh was made"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/evev.SoftMesh", IDName: "soft-mesh", Doc: "SoftMesh is a dynamic mesh that draws an eve.Soft body, as a two-sided\nsurface of its Tris if it has any (e.g., cloth), and otherwise as thin\nsquare tubes along its Links (e.g., a rope).  The vertices are in the\nlocal coordinates of the Soft node, and are updated from its current\npoint positions on each View.UpdatePose.", Embeds: []types.Field{{Name: "MeshBase"}}, Fields: []types.Field{{Name: "Soft", Doc: "the soft body that is drawn"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/eve/v2/evev.View", IDName: "view", Doc: "View connects a Virtual World with a Xyz Scene to visualize the world,\nincluding ability to render offscreen", Fields: []types.Field{{Name: "World", Doc: "the root Group node of the virtual world"}, {Name: "Scene", Doc: "the scene object for visualizing"}, {Name: "Root", Doc: "the root Group node in the Scene under which the world is rendered"}, {Name: "dynMeshes", Doc: "true if the world has Soft bodies or Particles, whose meshes are updated in UpdatePose"}}})
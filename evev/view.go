@@ -29,6 +29,9 @@ type View struct {
 
 	// the root Group node in the Scene under which the world is rendered
 	Root *xyz.Group
+
+	// true if the world has Soft bodies or Particles, whose meshes are updated in UpdatePose
+	dynMeshes bool
 }
 
 // NewView returns a new View that links given world with given scene and root group
@@ -50,10 +53,14 @@ func (vw *View) Sync() bool {
 	return rval
 }
 
-// UpdatePose updates the view pose values only from world tree.
+// UpdatePose updates the view pose values only from world tree,
+// along with the meshes of any Soft bodies and Particles.
 // Essential that both trees are already synchronized.
 func (vw *View) UpdatePose() {
 	vw.UpdatePoseNode(vw.World, vw.Root)
+	if vw.dynMeshes && !vw.Scene.Is(xyz.ScNeedsConfig) {
+		vw.Scene.UpdateMeshes()
+	}
 	vw.Scene.NeedsUpdate()
 }
 
@@ -118,10 +125,14 @@ func (vw *View) InitLibraryBody(wn eve.Node, sc *xyz.Scene) {
 	}
 }
 
-// InitLibSolid initializes Scene library with Solid for given body
+// InitLibSolid initializes Scene library with Solid for given body.
+// Proxy bodies are skipped, as they show their own Vis library entry.
 func (vw *View) InitLibSolid(bod eve.Body, sc *xyz.Scene) {
 	nm := bod.Name()
 	bb := bod.AsBodyBase()
+	if bb.Is(eve.Proxy) {
+		return
+	}
 	if bb.Vis == "" {
 		bb.Vis = nm
 	}
@@ -132,7 +143,7 @@ func (vw *View) InitLibSolid(bod eve.Body, sc *xyz.Scene) {
 	sld := xyz.NewSolid(lgp, nm)
 	wt := bod.NodeType().ShortName()
 	switch wt {
-	case "eve.Box":
+	case "eve.Box", "eve.Hull":
 		mnm := "eveBox"
 		bm := sc.MeshByName(mnm)
 		if bm == nil {
@@ -163,8 +174,12 @@ func (vw *View) InitLibSolid(bod eve.Body, sc *xyz.Scene) {
 	}
 }
 
-// ConfigBodySolid configures a solid for a body with current values
+// ConfigBodySolid configures a solid for a body with current values.
+// The Vis of Proxy bodies is shown as is.
 func (vw *View) ConfigBodySolid(bod eve.Body, sld *xyz.Solid) {
+	if bod.AsNodeBase().Is(eve.Proxy) {
+		return
+	}
 	wt := bod.NodeType().ShortName()
 	switch wt {
 	case "eve.Box":
@@ -191,6 +206,14 @@ func (vw *View) ConfigBodySolid(bod eve.Body, sld *xyz.Solid) {
 		if sp.Color != "" {
 			sld.Mat.Color = errors.Log1(colors.FromString(sp.Color))
 		}
+	case "eve.Hull":
+		hl := bod.(*eve.Hull)
+		lb := hl.Bounds()
+		sld.Pose.Pos = lb.Center()
+		sld.Pose.Scale = lb.Size()
+		if hl.Color != "" {
+			sld.Mat.Color = errors.Log1(colors.FromString(hl.Color))
+		}
 	}
 }
 
@@ -200,12 +223,28 @@ func (vw *View) ConfigView(wn eve.Node, vn xyz.Node, sc *xyz.Scene) {
 	vb := vn.(*xyz.Group)
 	vb.Pose.Pos = wb.Rel.Pos
 	vb.Pose.Quat = wb.Rel.Quat
+	vb.Pose.Scale = wb.Rel.ScaleFactor()
+	switch nd := wn.(type) {
+	case *eve.Soft:
+		vw.ConfigSoft(nd, vb, sc)
+		return
+	case *eve.Particles:
+		vw.ConfigParticles(nd, vb, sc)
+		return
+	}
 	bod := wn.AsBody()
 	if bod == nil {
 		return
 	}
+	vis := bod.AsBodyBase().Vis
+	if vis == "" { // e.g., an invisible Proxy
+		return
+	}
+	if !vb.HasChildren() {
+		sc.AddFromLibrary(vis, vb)
+	}
 	if !vb.HasChildren() {
-		sc.AddFromLibrary(bod.AsBodyBase().Vis, vb)
+		return
 	}
 	bgp := vb.Child(0)
 	if bgp.HasChildren() {
@@ -260,6 +299,7 @@ func (vw *View) UpdatePoseNode(wn eve.Node, vn xyz.Node) {
 		vb := vk.AsNode()
 		vb.Pose.Pos = wb.Rel.Pos
 		vb.Pose.Quat = wb.Rel.Quat
+		vb.Pose.Scale = wb.Rel.ScaleFactor()
 		vw.UpdatePoseNode(wk, vk)
 	}
 }
@@ -280,7 +320,7 @@ func (vw *View) UpdateBodyViewNode(bodyNames []string, wn eve.Node, vn xyz.Node)
 				}
 			}
 		}
-		if match {
+		if match && vk.HasChildren() {
 			wb := wk.(eve.Body)
 			bgp := vk.Child(0)
 			if bgp.HasChildren() {
@@ -6,4 +6,4 @@ import (
 	"cogentcore.org/core/types"
 )
 
-var _ = types.AddType(&types.Type{Name: "main.Env", IDName: "env", Doc: "Env encapsulates the virtual environment", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Methods: []types.Method{{Name: "WorldInit", Doc: "InitWorld does init on world and re-syncs", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "ReMakeWorld", Doc: "ReMakeWorld rebuilds the world and re-syncs with gui", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "GrabEyeImg", Doc: "GrabEyeImg takes a snapshot from the perspective of Emer's right eye", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "StepForward", Doc: "StepForward moves Emer forward in current facing direction one step, and takes GrabEyeImg", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "StepBackward", Doc: "StepBackward moves Emer backward in current facing direction one step, and takes GrabEyeImg", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "RotBodyLeft", Doc: "RotBodyLeft rotates emer left and takes GrabEyeImg", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "RotBodyRight", Doc: "RotBodyRight rotates emer right and takes GrabEyeImg", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "RotHeadLeft", Doc: "RotHeadLeft rotates emer left and takes GrabEyeImg", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "RotHeadRight", Doc: "RotHeadRight rotates emer right and takes GrabEyeImg", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}}, Fields: []types.Field{{Name: "EmerHt", Doc: "height of emer"}, {Name: "MoveStep", Doc: "how far to move every step"}, {Name: "RotStep", Doc: "how far to rotate every step"}, {Name: "Width", Doc: "width of room"}, {Name: "Depth", Doc: "depth of room"}, {Name: "Height", Doc: "height of room"}, {Name: "Thick", Doc: "thickness of walls of room"}, {Name: "DepthVals", Doc: "current depth map"}, {Name: "Camera", Doc: "offscreen render camera settings"}, {Name: "DepthMap", Doc: "color map to use for rendering depth map"}, {Name: "World", Doc: "world"}, {Name: "View3D", Doc: "3D view of world"}, {Name: "View2D", Doc: "view of world"}, {Name: "SceneView", Doc: "3D visualization of the Scene"}, {Name: "Scene2D", Doc: "2D visualization of the Scene"}, {Name: "Emer", Doc: "emer group"}, {Name: "EyeR", Doc: "Right eye of emer"}, {Name: "Contacts", Doc: "contacts from last step, for body"}, {Name: "EyeRImg", Doc: "snapshot bitmap view"}, {Name: "DepthImage", Doc: "depth map bitmap view"}}})
+var _ = types.AddType(&types.Type{Name: "main.Env", IDName: "env", Doc: "Env encapsulates the virtual environment", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Methods: []types.Method{{Name: "WorldInit", Doc: "InitWorld does init on world and re-syncs", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "ReMakeWorld", Doc: "ReMakeWorld rebuilds the world and re-syncs with gui", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "GrabEyeImg", Doc: "GrabEyeImg takes a snapshot from the perspective of Emer's right eye", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "StepForward", Doc: "StepForward moves Emer forward in current facing direction one step, and takes GrabEyeImg", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "StepBackward", Doc: "StepBackward moves Emer backward in current facing direction one step, and takes GrabEyeImg", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "RotBodyLeft", Doc: "RotBodyLeft rotates emer left and takes GrabEyeImg", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "RotBodyRight", Doc: "RotBodyRight rotates emer right and takes GrabEyeImg", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "RotHeadLeft", Doc: "RotHeadLeft rotates emer left and takes GrabEyeImg", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "RotHeadRight", Doc: "RotHeadRight rotates emer right and takes GrabEyeImg", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}}, Fields: []types.Field{{Name: "EmerHt", Doc: "height of emer"}, {Name: "MoveStep", Doc: "how far to move every step"}, {Name: "RotStep", Doc: "how far to rotate every step"}, {Name: "Width", Doc: "width of room"}, {Name: "Depth", Doc: "depth of room"}, {Name: "Height", Doc: "height of room"}, {Name: "Thick", Doc: "thickness of walls of room"}, {Name: "DepthVals", Doc: "current depth map"}, {Name: "Camera", Doc: "offscreen render camera settings"}, {Name: "DepthMap", Doc: "color map to use for rendering depth map"}, {Name: "World", Doc: "world"}, {Name: "View3D", Doc: "3D view of world"}, {Name: "View2D", Doc: "view of world"}, {Name: "SceneView", Doc: "3D visualization of the Scene"}, {Name: "Scene2D", Doc: "2D visualization of the Scene"}, {Name: "Emer", Doc: "emer group"}, {Name: "Char", Doc: "character controller that moves emer, sliding along walls"}, {Name: "EyeR", Doc: "Right eye of emer"}, {Name: "Contacts", Doc: "contacts from last step, for body"}, {Name: "EyeRImg", Doc: "snapshot bitmap view"}, {Name: "DepthImage", Doc: "depth map bitmap view"}}})
@@ -99,6 +99,9 @@ type Env struct { //types:add
 	// emer group
 	Emer *eve.Group `view:"-"`
 
+	// character controller that moves emer, sliding along walls
+	Char *eve.Character `view:"-"`
+
 	// Right eye of emer
 	EyeR eve.Body `view:"-"`
 
@@ -146,6 +149,7 @@ func (ev *Env) MakeWorld() {
 
 	MakeRoom(ev.World, "room1", ev.Width, ev.Depth, ev.Height, ev.Thick)
 	ev.Emer = MakeEmer(ev.World, ev.EmerHt)
+	ev.Char = eve.NewCharacter(ev.World, ev.Emer, ev.Emer.ChildByName("body", 0).(eve.Body))
 	ev.EyeR = ev.Emer.ChildByName("head", 1).ChildByName("eye-r", 2).(eve.Body)
 
 	ev.World.WorldInit()
@@ -257,26 +261,34 @@ func (ev *Env) WorldStep() {
 			}
 		}
 	}
-	if len(ev.Contacts) > 1 { // turn around
-		fmt.Printf("hit wall: turn around!\n")
-		rot := 100.0 + 90.0*rand.Float32()
-		ev.Emer.Rel.RotateOnAxis(0, 1, 0, rot)
-	}
 	ev.View3D.UpdatePose()
 	ev.View2D.UpdatePose()
 	ev.GrabEyeImg()
 	ev.UpdateViews()
 }
 
+// TurnIfBlocked turns Emer around by a random amount if its last move
+// was blocked by a wall
+func (ev *Env) TurnIfBlocked() {
+	if !ev.Char.Blocked {
+		return
+	}
+	fmt.Printf("hit wall: turn around!\n")
+	rot := 100.0 + 90.0*rand.Float32()
+	ev.Emer.Rel.RotateOnAxis(0, 1, 0, rot)
+}
+
 // StepForward moves Emer forward in current facing direction one step, and takes GrabEyeImg
 func (ev *Env) StepForward() { //types:add
-	ev.Emer.Rel.MoveOnAxis(0, 0, 1, -ev.MoveStep)
+	ev.Char.Move(math32.Vec3(0, 0, -ev.MoveStep).MulQuat(ev.Emer.Rel.Quat))
+	ev.TurnIfBlocked()
 	ev.WorldStep()
 }
 
 // StepBackward moves Emer backward in current facing direction one step, and takes GrabEyeImg
 func (ev *Env) StepBackward() { //types:add
-	ev.Emer.Rel.MoveOnAxis(0, 0, 1, ev.MoveStep)
+	ev.Char.Move(math32.Vec3(0, 0, ev.MoveStep).MulQuat(ev.Emer.Rel.Quat))
+	ev.TurnIfBlocked()
 	ev.WorldStep()
 }
 